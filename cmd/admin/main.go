@@ -0,0 +1,353 @@
+// Command admin manages admin accounts, sessions and JWT signing keys from a
+// shell, for example inside the production container:
+//
+//	admin user create --email me@example.com --role owner [--password-stdin]
+//	admin user reset-password --email me@example.com [--password-stdin]
+//	admin user list
+//	admin user set-role --email me@example.com --role owner
+//	admin user disable --email me@example.com
+//	admin user enable --email me@example.com
+//	admin token revoke-all [--email me@example.com]
+//	admin jwt rotate [--algorithm EdDSA|RS256]
+//
+// Changes are recorded in the audit log with "cmd/admin" as user agent.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/database"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+	"github.com/prakoso-id/personal-backend/internal/mailer"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/auth"
+	"github.com/prakoso-id/personal-backend/internal/password"
+	"gorm.io/gorm/logger"
+)
+
+const usage = `Usage: admin <command> [flags]
+
+Commands:
+  user create          Create an account (--email, --role, --password-stdin)
+  user reset-password  Set a new password and end all sessions (--email, --password-stdin)
+  user list            List all accounts
+  user set-role        Change the role of an account (--email, --role)
+  user disable         Block an account from signing in (--email)
+  user enable          Allow a disabled account to sign in again (--email)
+  token revoke-all     End every session, or those of one account (--email)
+  jwt rotate           Generate a new JWT signing key (--algorithm)
+
+Without --password-stdin a temporary password is generated and printed.
+`
+
+// actor identifies changes made with this tool in the audit log.
+var actor = audit.Actor{UserAgent: "cmd/admin"}
+
+// app holds the auth repository and service, connected on first use so that
+// commands without database access work offline.
+type app struct {
+	cfg     *config.Config
+	repo    auth.Repository
+	service auth.Service
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1] + " " + os.Args[2]
+	args := os.Args[3:]
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	a := &app{cfg: cfg}
+
+	switch command {
+	case "user create":
+		err = a.userCreate(args)
+	case "user reset-password":
+		err = a.userResetPassword(args)
+	case "user list":
+		err = a.userList(args)
+	case "user set-role":
+		err = a.userSetRole(args)
+	case "user disable":
+		err = a.userSetDisabled(args, true)
+	case "user enable":
+		err = a.userSetDisabled(args, false)
+	case "token revoke-all":
+		err = a.tokenRevokeAll(args)
+	case "jwt rotate":
+		err = a.jwtRotate(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
+	}
+}
+
+func (a *app) connect() {
+	if a.service != nil {
+		return
+	}
+
+	db, err := database.Connect(a.cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Error)
+
+	keys, err := keyring.Load(a.cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+
+	a.repo = auth.NewRepository(db)
+	auditService := audit.NewService(audit.NewRepository(db), a.cfg)
+	a.service = auth.NewService(a.repo, a.cfg, mailer.New(a.cfg), keys, auditService)
+}
+
+func (a *app) findUser(email string) (*auth.User, error) {
+	if email == "" {
+		return nil, errors.New("--email is required")
+	}
+	a.connect()
+
+	user, err := a.repo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+	return user, nil
+}
+
+// readPassword reads the first line of standard input.
+func readPassword() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	plain := strings.TrimRight(line, "\r\n")
+	if plain == "" {
+		return "", errors.New("no password on standard input")
+	}
+	return plain, nil
+}
+
+func describePasswordError(err error) error {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return fmt.Errorf("password rejected: %s", policyErr.Error())
+	}
+	return err
+}
+
+func (a *app) userCreate(args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ExitOnError)
+	email := fs.String("email", "", "email address of the new account")
+	role := fs.String("role", auth.RoleOwner, "role: owner, editor or viewer")
+	fromStdin := fs.Bool("password-stdin", false, "read the password from standard input")
+	fs.Parse(args)
+
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+
+	var plain string
+	if *fromStdin {
+		var err error
+		if plain, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	a.connect()
+	if plain != "" {
+		user, err := a.service.CreateUser(actor, *email, *role, plain)
+		if err != nil {
+			return describePasswordError(err)
+		}
+		fmt.Printf("Created %s (%s) with id %s\n", user.Email, user.Role, user.ID)
+		return nil
+	}
+
+	user, tempPassword, err := a.service.InviteUser(actor, *email, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s (%s) with id %s\n", user.Email, user.Role, user.ID)
+	fmt.Printf("Temporary password: %s\n", tempPassword)
+	return nil
+}
+
+func (a *app) userResetPassword(args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+	email := fs.String("email", "", "email address of the account")
+	fromStdin := fs.Bool("password-stdin", false, "read the new password from standard input")
+	fs.Parse(args)
+
+	var plain string
+	if *fromStdin {
+		var err error
+		if plain, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	user, err := a.findUser(*email)
+	if err != nil {
+		return err
+	}
+
+	newPassword, err := a.service.SetUserPassword(actor, user.ID, plain)
+	if err != nil {
+		return describePasswordError(err)
+	}
+	fmt.Printf("Password of %s changed; all sessions ended\n", user.Email)
+	if plain == "" {
+		fmt.Printf("Temporary password: %s\n", newPassword)
+	}
+	return nil
+}
+
+func (a *app) userList(args []string) error {
+	fs := flag.NewFlagSet("user list", flag.ExitOnError)
+	fs.Parse(args)
+
+	a.connect()
+	users, err := a.service.ListUsers()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tROLE\t2FA\tSTATUS\tCREATED")
+	for _, user := range users {
+		status := "active"
+		if user.DisabledAt != nil {
+			status = "disabled"
+		}
+		twoFactor := "off"
+		if user.TOTPEnabled {
+			twoFactor = "on"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			user.ID, user.Email, user.Role, twoFactor, status, user.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func (a *app) userSetRole(args []string) error {
+	fs := flag.NewFlagSet("user set-role", flag.ExitOnError)
+	email := fs.String("email", "", "email address of the account")
+	role := fs.String("role", "", "role: owner, editor or viewer")
+	fs.Parse(args)
+
+	if *role == "" {
+		return errors.New("--role is required")
+	}
+	user, err := a.findUser(*email)
+	if err != nil {
+		return err
+	}
+
+	user, err = a.service.UpdateUserRole(actor, user.ID, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is now %s\n", user.Email, user.Role)
+	return nil
+}
+
+func (a *app) userSetDisabled(args []string, disabled bool) error {
+	name := "user enable"
+	if disabled {
+		name = "user disable"
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	email := fs.String("email", "", "email address of the account")
+	fs.Parse(args)
+
+	user, err := a.findUser(*email)
+	if err != nil {
+		return err
+	}
+
+	if disabled {
+		if err := a.service.DisableUser(actor, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Disabled %s; all sessions ended\n", user.Email)
+		return nil
+	}
+
+	if err := a.service.EnableUser(actor, user.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Enabled %s\n", user.Email)
+	return nil
+}
+
+func (a *app) tokenRevokeAll(args []string) error {
+	fs := flag.NewFlagSet("token revoke-all", flag.ExitOnError)
+	email := fs.String("email", "", "only end the sessions of this account")
+	fs.Parse(args)
+
+	if *email != "" {
+		user, err := a.findUser(*email)
+		if err != nil {
+			return err
+		}
+		if err := a.service.RevokeAllSessions(actor, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Ended all sessions of %s\n", user.Email)
+		return nil
+	}
+
+	a.connect()
+	if err := a.service.RevokeEverySession(actor); err != nil {
+		return err
+	}
+	fmt.Println("Ended all sessions of every user; API keys stay valid")
+	return nil
+}
+
+func (a *app) jwtRotate(args []string) error {
+	fs := flag.NewFlagSet("jwt rotate", flag.ExitOnError)
+	algorithm := fs.String("algorithm", a.cfg.JWT.Algorithm, "key type: EdDSA or RS256")
+	fs.Parse(args)
+
+	if a.cfg.JWT.KeysDir == "" {
+		return errors.New("JWT_KEYS_DIR is empty; tokens are signed with JWT_SECRET, change that secret instead")
+	}
+
+	kid, err := keyring.Generate(a.cfg.JWT.KeysDir, *algorithm)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generated key %s in %s\n", kid, a.cfg.JWT.KeysDir)
+	if a.cfg.JWT.ActiveKeyID != "" {
+		fmt.Printf("JWT_ACTIVE_KID pins %s; set it to %s to sign with the new key\n", a.cfg.JWT.ActiveKeyID, kid)
+	}
+	fmt.Println("Restart the server to start signing with it. Old keys keep verifying until their files are removed.")
+	return nil
+}
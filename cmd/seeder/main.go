@@ -15,6 +15,7 @@ import (
 	"github.com/prakoso-id/personal-backend/internal/modules/posts"
 	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
 	"github.com/prakoso-id/personal-backend/internal/modules/projects"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
 	"github.com/prakoso-id/personal-backend/internal/modules/skills"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -96,12 +97,25 @@ func main() {
 	}
 	log.Println("Contact messages seeded.")
 
+	// Seeded rows bypass the repositories, which render the content and
+	// maintain the search index
+	if err := posts.RenderMissing(db); err != nil {
+		log.Fatalf("Failed to render posts: %v", err)
+	}
+	if err := projects.RenderMissing(db); err != nil {
+		log.Fatalf("Failed to render projects: %v", err)
+	}
+	if err := search.Reindex(db, true); err != nil {
+		log.Fatalf("Failed to rebuild search index: %v", err)
+	}
+	log.Println("Search index rebuilt.")
+
 	log.Println("Seeding completed successfully!")
 }
 
 func cleanDB(db *gorm.DB) error {
 	// Disable foreign key checks to allow truncation
-	if err := db.Exec("TRUNCATE TABLE users, profiles, skills, profile_skills, experiences, social_links, projects, project_skills, tags, posts, post_tags, images, contact_messages RESTART IDENTITY CASCADE").Error; err != nil {
+	if err := db.Exec("TRUNCATE TABLE users, refresh_tokens, recovery_codes, login_challenges, login_throttles, password_reset_tokens, magic_link_tokens, api_keys, sessions, oidc_login_states, passkeys, passkey_ceremonies, password_histories, profiles, skills, profile_skills, experiences, social_links, projects, project_skills, tags, posts, post_tags, images, contact_messages, audit_logs, revisions, revision_drafts, slug_history, series, series_posts RESTART IDENTITY CASCADE").Error; err != nil {
 		return err
 	}
 	return nil
@@ -116,6 +130,7 @@ func seedUsers(db *gorm.DB) (*auth.User, error) {
 	user := auth.User{
 		Email:        "admin@example.com",
 		PasswordHash: string(hashedPassword),
+		Role:         auth.RoleOwner,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -1,15 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prakoso-id/personal-backend/internal/config"
 	"github.com/prakoso-id/personal-backend/internal/database"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
 	"github.com/prakoso-id/personal-backend/internal/middleware"
+	"github.com/prakoso-id/personal-backend/internal/netpolicy"
 	"github.com/prakoso-id/personal-backend/internal/routes"
+	"github.com/prakoso-id/personal-backend/internal/scheduler"
 )
 
+// shutdownTimeout bounds how long running requests may take to finish once
+// the server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
 // @title           Personal Website API
 // @version         1.0
 // @description     Backend API for Personal Website.
@@ -45,6 +59,18 @@ func main() {
 	// Migrate Database
 	database.Migrate(db)
 
+	// Load JWT signing keys
+	keys, err := keyring.Load(cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+
+	// Load admin network policy
+	policy, err := netpolicy.New(cfg.Network)
+	if err != nil {
+		log.Fatalf("Failed to load network policy: %v", err)
+	}
+
 	// Setup Gin
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -56,11 +82,31 @@ func main() {
 	r.Use(middleware.CORSMiddleware())
 
 	// Routes
-	routes.RegisterRoutes(r, db, cfg)
+	jobs := routes.RegisterRoutes(r, db, cfg, keys, policy)
+
+	// Stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs
+	schedulerDone := scheduler.Start(ctx, time.Duration(cfg.Schedule.Interval)*time.Second, jobs...)
 
 	// Run Server
-	log.Printf("Server running on port %s", cfg.Server.Port)
-	if err := r.Run(":" + cfg.Server.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{Addr: ":" + cfg.Server.Port, Handler: r}
+	go func() {
+		log.Printf("Server running on port %s", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server: %v", err)
 	}
+	<-schedulerDone
 }
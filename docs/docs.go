@@ -24,33 +24,51 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/experiences": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/admin/2fa/disable": {
+            "post": {
+                "description": "Disables 2FA after verifying a TOTP or recovery code",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieve a list of all experiences for admin",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Experiences"
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Disable Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "description": "TOTP or Recovery Code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.TOTPCodeRequest"
+                        }
+                    }
                 ],
-                "summary": "Admin - Get All Experiences",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/experiences.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -58,15 +76,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Create a new experience",
+                ]
+            }
+        },
+        "/admin/2fa/enable": {
+            "post": {
+                "description": "Confirms the TOTP secret with a code, enables 2FA and returns one-time recovery codes",
                 "consumes": [
                     "application/json"
                 ],
@@ -74,25 +94,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Experiences"
+                    "Admin - Auth"
                 ],
-                "summary": "Admin - Create Experience",
+                "summary": "Admin - Enable Two-Factor Authentication",
                 "parameters": [
                     {
-                        "description": "Experience Data",
+                        "description": "TOTP Code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/experiences.CreateExperienceRequest"
+                            "$ref": "#/definitions/auth.TOTPCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/experiences.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -104,8 +127,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -113,17 +136,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/experiences/{id}": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update an existing experience",
+                ]
+            }
+        },
+        "/admin/2fa/recovery-codes": {
+            "post": {
+                "description": "Replaces all recovery codes after verifying a TOTP code. Previous codes stop working.",
                 "consumes": [
                     "application/json"
                 ],
@@ -131,24 +154,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Experiences"
+                    "Admin - Auth"
                 ],
-                "summary": "Admin - Update Experience",
+                "summary": "Admin - Regenerate Recovery Codes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Experience ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Experience Data",
+                        "description": "TOTP Code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/experiences.UpdateExperienceRequest"
+                            "$ref": "#/definitions/auth.TOTPCodeRequest"
                         }
                     }
                 ],
@@ -156,7 +172,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/experiences.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -168,8 +187,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -177,37 +196,32 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Delete an experience",
+                ]
+            }
+        },
+        "/admin/2fa/setup": {
+            "post": {
+                "description": "Generates a new TOTP secret and provisioning URI for an authenticator app. 2FA is not active until confirmed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Experiences"
-                ],
-                "summary": "Admin - Delete Experience",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Experience ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin - Auth"
                 ],
+                "summary": "Admin - Start Two-Factor Setup",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -219,8 +233,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -228,49 +242,32 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/images/upload": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Upload an image file to storage. Returns file details to be used in other endpoints.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                ]
+            }
+        },
+        "/admin/api-keys": {
+            "get": {
+                "description": "Retrieve all API keys with scopes, expiry, last use and revocation time. Secrets are never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Images"
-                ],
-                "summary": "Admin - Upload Image",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "Image File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "Admin - API Keys"
                 ],
+                "summary": "Admin - Get All API Keys",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/images.ImageUploadResult"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
                             }
                         }
                     },
@@ -283,36 +280,39 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/images/{id}": {
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            },
+            "post": {
+                "description": "Create a scoped API key for scripts and CI. Scopes use permission names such as posts:write, images:upload or messages:read. The key is returned only once.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Delete an image",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Images"
+                    "Admin - API Keys"
                 ],
-                "summary": "Admin - Delete Image",
+                "summary": "Admin - Create API Key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Image ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "API Key Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.CreateAPIKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -327,8 +327,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -336,31 +336,31 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/admin/login": {
-            "post": {
-                "description": "Authenticates an admin user and returns a JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "description": "Revoke an API key immediately",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Auth"
+                    "Admin - API Keys"
                 ],
-                "summary": "Admin - Login",
+                "summary": "Admin - Revoke API Key",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -368,9 +368,7 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -382,8 +380,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -391,61 +389,24 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/messages": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Retrieve all contact messages",
+                ]
+            }
+        },
+        "/admin/audit": {
+            "get": {
+                "description": "Retrieve a paginated list of changes made through the admin API, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Contact"
+                    "Admin - Audit"
                 ],
-                "summary": "Admin - Get All Messages",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/contact.ContactMessage"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/posts": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve a paginated list of all posts (including unpublished)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin - Posts"
-                ],
-                "summary": "Admin - Get All Posts",
+                "summary": "Admin - Get Audit Log",
                 "parameters": [
                     {
                         "type": "integer",
@@ -460,6 +421,42 @@ const docTemplate = `{
                         "description": "Items per page",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action (create, update, delete, upload, revoke, enable, disable, regenerate, publish, unpublish, restore)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity type (post, project, skill, profile, ...)",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes at or after this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes before this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -469,6 +466,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/pagination.PaginatedResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -478,15 +484,52 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            }
+        },
+        "/admin/experiences": {
+            "get": {
+                "description": "Retrieve a list of all experiences for admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Experiences"
                 ],
-                "description": "Create a new post",
+                "summary": "Admin - Get All Experiences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/experiences.Experience"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new experience",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,17 +537,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Posts"
+                    "Admin - Experiences"
                 ],
-                "summary": "Admin - Create Post",
+                "summary": "Admin - Create Experience",
                 "parameters": [
                     {
-                        "description": "Post Data",
+                        "description": "Experience Data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/posts.CreatePostRequest"
+                            "$ref": "#/definitions/experiences.CreateExperienceRequest"
                         }
                     }
                 ],
@@ -512,7 +555,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/posts.Post"
+                            "$ref": "#/definitions/experiences.Experience"
                         }
                     },
                     "400": {
@@ -533,17 +576,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/posts/{id}": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update an existing post",
+                ]
+            }
+        },
+        "/admin/experiences/{id}": {
+            "put": {
+                "description": "Update an existing experience",
                 "consumes": [
                     "application/json"
                 ],
@@ -551,24 +594,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Posts"
+                    "Admin - Experiences"
                 ],
-                "summary": "Admin - Update Post",
+                "summary": "Admin - Update Experience",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Post ID",
+                        "description": "Experience ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Post Data",
+                        "description": "Experience Data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/posts.UpdatePostRequest"
+                            "$ref": "#/definitions/experiences.UpdateExperienceRequest"
                         }
                     }
                 ],
@@ -576,7 +619,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/posts.Post"
+                            "$ref": "#/definitions/experiences.Experience"
                         }
                     },
                     "400": {
@@ -597,26 +640,26 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Delete a post",
+                ]
+            },
+            "delete": {
+                "description": "Delete an experience",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Posts"
+                    "Admin - Experiences"
                 ],
-                "summary": "Admin - Delete Post",
+                "summary": "Admin - Delete Experience",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Post ID",
+                        "description": "Experience ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -648,58 +691,94 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/admin/profile": {
-            "put": {
+        "/admin/experiences/{id}/autosave": {
+            "get": {
+                "description": "Retrieve the caller's autosaved draft. base_revision is the latest revision when it was saved; if the entity has newer revisions, the draft is stale.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update details of the user profile. Avatar and resume are uploaded as files.",
+                ]
+            },
+            "put": {
+                "description": "Store unsaved editor content as the caller's draft, replacing the previous one. The content is a JSON object with the fields of the entity's update request, all optional; unknown fields and wrong types are rejected, and bodies over 1 MiB answer 413. Drafts do not create revisions and are discarded when the caller saves the entity.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Profile"
+                    "Admin - Revisions"
                 ],
-                "summary": "Admin - Update Profile",
+                "summary": "Admin - Autosave Draft",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Full Name",
-                        "name": "full_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Bio",
-                        "name": "bio",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Avatar Image (jpg, jpeg, png, webp - max 5MB)",
-                        "name": "avatar",
-                        "in": "formData"
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Resume File (pdf, doc, docx - max 10MB)",
-                        "name": "resume",
-                        "in": "formData"
+                        "description": "Draft content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/profiles.Profile"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -711,8 +790,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -720,8 +799,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -729,49 +808,41 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/projects": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Retrieve a paginated list of all projects",
+                ]
+            },
+            "delete": {
+                "description": "Delete the caller's autosaved draft",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Projects"
+                    "Admin - Revisions"
                 ],
-                "summary": "Admin - Get All Projects",
+                "summary": "Admin - Discard Autosaved Draft",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -779,41 +850,52 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Create a new project",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/experiences/{id}/revisions": {
+            "get": {
+                "description": "Retrieve the saved revisions of a post, project, experience or profile, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Projects"
+                    "Admin - Revisions"
                 ],
-                "summary": "Admin - Create Project",
+                "summary": "Admin - List Revisions",
                 "parameters": [
                     {
-                        "description": "Project Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/projects.CreateProjectRequest"
-                        }
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/projects.Project"
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
                         }
                     },
                     "400": {
@@ -834,50 +916,54 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/projects/{id}": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update an existing project",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/experiences/{id}/revisions/diff": {
+            "get": {
+                "description": "Compare two revisions field by field. Multi-line text such as markdown also gets a unified line diff. Without \"to\" the latest revision is used, without \"from\" the one before \"to\".",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Projects"
+                    "Admin - Revisions"
                 ],
-                "summary": "Admin - Update Project",
+                "summary": "Admin - Diff Revisions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
+                        "description": "Entity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Project Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/projects.UpdateProjectRequest"
-                        }
+                        "type": "integer",
+                        "description": "Older revision number",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision number",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/projects.Project"
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
                         }
                     },
                     "400": {
@@ -889,8 +975,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -898,29 +984,38 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Delete a project",
+                ]
+            }
+        },
+        "/admin/experiences/{id}/revisions/{number}": {
+            "get": {
+                "description": "Retrieve one revision including the saved fields",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Projects"
+                    "Admin - Revisions"
                 ],
-                "summary": "Admin - Delete Project",
+                "summary": "Admin - Get Revision",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
+                        "description": "Entity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -940,8 +1035,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -949,43 +1044,46 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/skills": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Create a new skill",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/experiences/{id}/revisions/{number}/restore": {
+            "post": {
+                "description": "Put the fields saved in a revision back into the experience. The result is stored as a new revision; history is never rewritten.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Skills"
+                    "Admin - Experiences"
                 ],
-                "summary": "Admin - Create Skill",
+                "summary": "Admin - Restore Experience Revision",
                 "parameters": [
                     {
-                        "description": "Skill Data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/skills.CreateSkillRequest"
-                        }
+                        "type": "string",
+                        "description": "Experience ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.Skill"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -997,6 +1095,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1006,17 +1113,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/skills/{id}": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update an existing skill",
+                ]
+            }
+        },
+        "/admin/forgot-password": {
+            "post": {
+                "description": "Emails a single-use password reset link if the address belongs to an account. The response is the same either way. Requests are limited per email and per client IP; over the limit the answer is 429 with Retry-After.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1024,24 +1131,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Skills"
+                    "Admin - Auth"
                 ],
-                "summary": "Admin - Update Skill",
+                "summary": "Admin - Forgot Password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Skill ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Skill Data",
+                        "description": "Email",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/skills.UpdateSkillRequest"
+                            "$ref": "#/definitions/auth.ForgotPasswordRequest"
                         }
                     }
                 ],
@@ -1049,7 +1149,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/skills.Skill"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1061,8 +1164,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1080,36 +1183,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/admin/images/upload": {
+            "post": {
+                "description": "Upload an image file to storage. Returns file details to be used in other endpoints.",
+                "consumes": [
+                    "multipart/form-data"
                 ],
-                "description": "Delete a skill",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Skills"
+                    "Admin - Images"
                 ],
-                "summary": "Admin - Delete Skill",
+                "summary": "Admin - Upload Image",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Skill ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Image File",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/images.ImageUploadResult"
                         }
                     },
                     "400": {
@@ -1130,35 +1232,88 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/admin/update-email": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update the authenticated admin's email address",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/images/{id}": {
+            "delete": {
+                "description": "Delete an image",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Auth"
+                    "Admin - Images"
                 ],
-                "summary": "Admin - Update Email",
+                "summary": "Admin - Delete Image",
                 "parameters": [
                     {
-                        "description": "New Email",
-                        "name": "request",
-                        "in": "body",
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/login": {
+            "post": {
+                "description": "Authenticates an admin user and returns a short-lived JWT access token and a refresh token. If two-factor authentication is enabled, a challenge token is returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "request",
+                        "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.UpdateEmailRequest"
+                            "$ref": "#/definitions/auth.LoginRequest"
                         }
                     }
                 ],
@@ -1190,8 +1345,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1202,14 +1357,99 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/update-password": {
-            "put": {
+        "/admin/login-lockouts": {
+            "get": {
+                "description": "Lists failed-login counters per email and client IP, including active back-offs and lockouts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Get Login Lockouts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/auth.LoginThrottle"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            }
+        },
+        "/admin/login-lockouts/{id}": {
+            "delete": {
+                "description": "Resets the failed-login counter and lifts any lockout for one email or client IP",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Clear Login Lockout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lockout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "description": "Update the authenticated admin's password",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/login/2fa": {
+            "post": {
+                "description": "Completes a login challenge with a TOTP code or an unused recovery code and returns the tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -1219,15 +1459,15 @@ const docTemplate = `{
                 "tags": [
                     "Admin - Auth"
                 ],
-                "summary": "Admin - Update Password",
+                "summary": "Admin - Complete Two-Factor Login",
                 "parameters": [
                     {
-                        "description": "New Password",
+                        "description": "Challenge and Code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.UpdatePasswordRequest"
+                            "$ref": "#/definitions/auth.TwoFactorLoginRequest"
                         }
                     }
                 ],
@@ -1259,8 +1499,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1271,9 +1511,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/contact": {
+        "/admin/login/magic-link": {
             "post": {
-                "description": "Send a contact message",
+                "description": "Emails a single-use sign-in link if the address belongs to an account and returns a nonce. Keep the nonce in the requesting browser; the link only works together with it. The response is the same whether or not the account exists. Each email address and client IP may request a limited number of links per window.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1281,25 +1521,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Public - Contact"
+                    "Admin - Auth"
                 ],
-                "summary": "Public - Send Contact Message",
+                "summary": "Admin - Request Magic Link",
                 "parameters": [
                     {
-                        "description": "Message",
+                        "description": "Email",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/contact.CreateMessageRequest"
+                            "$ref": "#/definitions/auth.MagicLinkRequestBody"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contact.ContactMessage"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1311,6 +1552,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1323,23 +1573,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/experiences": {
-            "get": {
-                "description": "Retrieve a list of all experiences",
+        "/admin/login/magic-link/verify": {
+            "post": {
+                "description": "Exchanges the token from the magic link email and the nonce returned when it was requested for the same tokens as a password login. If two-factor authentication is enabled, a challenge token is returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public - Experiences"
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Complete Magic Link Login",
+                "parameters": [
+                    {
+                        "description": "Token and Nonce",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.MagicLinkVerifyRequest"
+                        }
+                    }
                 ],
-                "summary": "Public - Get All Experiences",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/experiences.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1355,28 +1637,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/posts": {
-            "get": {
-                "description": "Retrieve a list of all published posts",
+        "/admin/login/passkey/begin": {
+            "post": {
+                "description": "Returns WebAuthn assertion options for navigator.credentials.get() and a ceremony token. Any discoverable passkey of this site can be used; no credentials are listed, so the response does not reveal which accounts exist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public - Posts"
+                    "Admin - Auth"
                 ],
-                "summary": "Public - Get All Posts",
+                "summary": "Admin - Start Passkey Login",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/posts.Post"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1387,25 +1667,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/profile": {
-            "get": {
-                "description": "Retrieve the user profile",
+        "/admin/login/passkey/finish": {
+            "post": {
+                "description": "Verifies the PublicKeyCredential returned by navigator.credentials.get() and returns the same tokens as a password login",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public - Profile"
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Complete Passkey Login",
+                "parameters": [
+                    {
+                        "description": "Ceremony Token and Credential",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.PasskeyLoginFinishRequest"
+                        }
+                    }
                 ],
-                "summary": "Public - Get Profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/profiles.Profile"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1413,8 +1710,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1425,83 +1722,4511 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/projects": {
-            "get": {
-                "description": "Retrieve a list of all projects",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Public - Projects"
-                ],
-                "summary": "Public - Get All Projects",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/projects.Project"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
+        "/admin/logout": {
+            "post": {
+                "description": "Revokes the refresh token and every token rotated from the same login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/messages": {
+            "get": {
+                "description": "Retrieve all contact messages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Contact"
+                ],
+                "summary": "Admin - Get All Messages",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/contact.ContactMessage"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/network-policy": {
+            "get": {
+                "description": "Shows how many networks are on each list and how many admin requests were denied since the server started, by reason (denied, not_allowed, sensitive, invalid_address)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Get Network Policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/oidc/authorize": {
+            "get": {
+                "description": "Starts an authorization-code + PKCE login with the configured OpenID Connect provider. Redirect the browser to authorization_url; the provider sends it back to OIDC_REDIRECT_URL with code and state.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Start OIDC Login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/oidc/callback": {
+            "post": {
+                "description": "Exchanges the code and state received from the identity provider for the normal access and refresh tokens. The subject or verified email must be allowlisted and match an existing user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Complete OIDC Login",
+                "parameters": [
+                    {
+                        "description": "Code and State",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.OIDCCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/passkeys": {
+            "get": {
+                "description": "List the passkeys registered for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Passkeys"
+                ],
+                "summary": "Admin - Get Passkeys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/passkeys/register/begin": {
+            "post": {
+                "description": "Returns WebAuthn creation options for navigator.credentials.create() and a ceremony token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Passkeys"
+                ],
+                "summary": "Admin - Start Passkey Registration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/passkeys/register/finish": {
+            "post": {
+                "description": "Verifies the PublicKeyCredential returned by navigator.credentials.create() and stores the passkey",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Passkeys"
+                ],
+                "summary": "Admin - Complete Passkey Registration",
+                "parameters": [
+                    {
+                        "description": "Ceremony Token, Name and Credential",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.FinishPasskeyRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/passkeys/{id}": {
+            "delete": {
+                "description": "Remove one of the authenticated user's passkeys",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Passkeys"
+                ],
+                "summary": "Admin - Delete Passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Passkey ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts": {
+            "get": {
+                "description": "Retrieve a paginated list of all posts (including unpublished)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Posts"
+                ],
+                "summary": "Admin - Get All Posts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new post. Set publish_at to publish it later and unpublish_at to withdraw it automatically. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Posts"
+                ],
+                "summary": "Admin - Create Post",
+                "parameters": [
+                    {
+                        "description": "Post Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.CreatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/posts.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}": {
+            "put": {
+                "description": "Update an existing post",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Posts"
+                ],
+                "summary": "Admin - Update Post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Post Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.UpdatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.Post"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a post",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Posts"
+                ],
+                "summary": "Admin - Delete Post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}/autosave": {
+            "get": {
+                "description": "Retrieve the caller's autosaved draft. base_revision is the latest revision when it was saved; if the entity has newer revisions, the draft is stale.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Store unsaved editor content as the caller's draft, replacing the previous one. The content is a JSON object with the fields of the entity's update request, all optional; unknown fields and wrong types are rejected, and bodies over 1 MiB answer 413. Drafts do not create revisions and are discarded when the caller saves the entity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Autosave Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Draft content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete the caller's autosaved draft",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Discard Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}/revisions": {
+            "get": {
+                "description": "Retrieve the saved revisions of a post, project, experience or profile, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - List Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}/revisions/diff": {
+            "get": {
+                "description": "Compare two revisions field by field. Multi-line text such as markdown also gets a unified line diff. Without \"to\" the latest revision is used, without \"from\" the one before \"to\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Diff Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older revision number",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision number",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}/revisions/{number}": {
+            "get": {
+                "description": "Retrieve one revision including the saved fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/posts/{id}/revisions/{number}/restore": {
+            "post": {
+                "description": "Put the fields saved in a revision back into the post. The result is stored as a new revision; history is never rewritten.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Posts"
+                ],
+                "summary": "Admin - Restore Post Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile": {
+            "put": {
+                "description": "Update details of the user profile. Avatar and resume are uploaded as files.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Profile"
+                ],
+                "summary": "Admin - Update Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Full Name",
+                        "name": "full_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bio",
+                        "name": "bio",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Avatar Image (jpg, jpeg, png, webp - max 5MB)",
+                        "name": "avatar",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Resume File (pdf, doc, docx - max 10MB)",
+                        "name": "resume",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/profiles.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile/{id}/autosave": {
+            "get": {
+                "description": "Retrieve the caller's autosaved draft. base_revision is the latest revision when it was saved; if the entity has newer revisions, the draft is stale.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Store unsaved editor content as the caller's draft, replacing the previous one. The content is a JSON object with the fields of the entity's update request, all optional; unknown fields and wrong types are rejected, and bodies over 1 MiB answer 413. Drafts do not create revisions and are discarded when the caller saves the entity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Autosave Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Draft content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete the caller's autosaved draft",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Discard Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile/{id}/revisions": {
+            "get": {
+                "description": "Retrieve the saved revisions of a post, project, experience or profile, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - List Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile/{id}/revisions/diff": {
+            "get": {
+                "description": "Compare two revisions field by field. Multi-line text such as markdown also gets a unified line diff. Without \"to\" the latest revision is used, without \"from\" the one before \"to\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Diff Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older revision number",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision number",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile/{id}/revisions/{number}": {
+            "get": {
+                "description": "Retrieve one revision including the saved fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/profile/{id}/revisions/{number}/restore": {
+            "post": {
+                "description": "Put the fields saved in a revision back into the profile. The result is stored as a new revision; history is never rewritten.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Profile"
+                ],
+                "summary": "Admin - Restore Profile Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects": {
+            "get": {
+                "description": "Retrieve a paginated list of all projects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Projects"
+                ],
+                "summary": "Admin - Get All Projects",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new project. visible_from and visible_until limit when it is shown publicly. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Projects"
+                ],
+                "summary": "Admin - Create Project",
+                "parameters": [
+                    {
+                        "description": "Project Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/projects.CreateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/projects.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}": {
+            "put": {
+                "description": "Update an existing project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Projects"
+                ],
+                "summary": "Admin - Update Project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Project Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/projects.UpdateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/projects.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Projects"
+                ],
+                "summary": "Admin - Delete Project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}/autosave": {
+            "get": {
+                "description": "Retrieve the caller's autosaved draft. base_revision is the latest revision when it was saved; if the entity has newer revisions, the draft is stale.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Store unsaved editor content as the caller's draft, replacing the previous one. The content is a JSON object with the fields of the entity's update request, all optional; unknown fields and wrong types are rejected, and bodies over 1 MiB answer 413. Drafts do not create revisions and are discarded when the caller saves the entity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Autosave Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Draft content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete the caller's autosaved draft",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Discard Autosaved Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}/revisions": {
+            "get": {
+                "description": "Retrieve the saved revisions of a post, project, experience or profile, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - List Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}/revisions/diff": {
+            "get": {
+                "description": "Compare two revisions field by field. Multi-line text such as markdown also gets a unified line diff. Without \"to\" the latest revision is used, without \"from\" the one before \"to\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Diff Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older revision number",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision number",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}/revisions/{number}": {
+            "get": {
+                "description": "Retrieve one revision including the saved fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Revisions"
+                ],
+                "summary": "Admin - Get Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/projects/{id}/revisions/{number}/restore": {
+            "post": {
+                "description": "Put the fields saved in a revision back into the project. The result is stored as a new revision; history is never rewritten.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Projects"
+                ],
+                "summary": "Admin - Restore Project Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. The presented refresh token is revoked; reusing it revokes the whole login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Refresh Token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reset-password": {
+            "post": {
+                "description": "Sets a new password using the token from the reset email and signs out every session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Reset Password",
+                "parameters": [
+                    {
+                        "description": "Token and New Password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schedule": {
+            "get": {
+                "description": "Lists upcoming scheduled changes, soonest first: posts to publish or unpublish and projects to show or hide",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Schedule"
+                ],
+                "summary": "Admin - Get Publishing Schedule",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/schedule.ics": {
+            "get": {
+                "description": "The upcoming scheduled changes as an iCalendar feed for calendar apps. Authenticate with an API key holding posts:read and projects:read.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Admin - Schedule"
+                ],
+                "summary": "Admin - Get Publishing Schedule (iCal)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/series": {
+            "get": {
+                "description": "Retrieve a paginated list of series with the number of posts in each, drafts included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Get All Series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.PaginatedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a series. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken. Add posts with PUT /admin/series/{id}/posts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Create Series",
+                "parameters": [
+                    {
+                        "description": "Series Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/series.CreateSeriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/series/{id}": {
+            "get": {
+                "description": "A series and all its posts in reading order, drafts included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Get Series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update the title, slug, description and cover of a series. Its old slug keeps redirecting to the new one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Update Series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Series Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/series.UpdateSeriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a series. Its posts are kept and no longer belong to a series.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Delete Series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/series/{id}/posts": {
+            "put": {
+                "description": "Replace the posts of a series with post_ids, in reading order. Send the current posts in a new order to reorder them, or an empty list to empty the series. A post belongs to one series at most; listing a post of another series answers 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Series"
+                ],
+                "summary": "Admin - Set Series Posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Posts in order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/series.SetPostsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/sessions": {
+            "get": {
+                "description": "List the authenticated user's active sessions (one per login). The session making the request is flagged as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Sessions"
+                ],
+                "summary": "Admin - Get Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Revoke every session of the authenticated user, including the current one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Sessions"
+                ],
+                "summary": "Admin - Log Out Everywhere",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/sessions/{id}": {
+            "delete": {
+                "description": "Log out one of the authenticated user's sessions. Its access and refresh tokens stop working immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Sessions"
+                ],
+                "summary": "Admin - Revoke Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/skills": {
+            "post": {
+                "description": "Create a new skill",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Skills"
+                ],
+                "summary": "Admin - Create Skill",
+                "parameters": [
+                    {
+                        "description": "Skill Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skills.CreateSkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/skills/{id}": {
+            "put": {
+                "description": "Update an existing skill",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Skills"
+                ],
+                "summary": "Admin - Update Skill",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Skill ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Skill Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/skills.UpdateSkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/skills.Skill"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a skill",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Skills"
+                ],
+                "summary": "Admin - Delete Skill",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Skill ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tags": {
+            "get": {
+                "description": "Every tag with the number of posts using it, drafts included. Tags with a count of 0 are unused.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tags"
+                ],
+                "summary": "Admin - Get All Tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tags/unused": {
+            "delete": {
+                "description": "Delete every tag that no post uses and return the deleted tags",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tags"
+                ],
+                "summary": "Admin - Delete Unused Tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tags/{id}": {
+            "put": {
+                "description": "Rename a tag and set its description. The slug follows the name; the old slug redirects to the tag with 301. Renaming to the name of another tag answers 409; merge the tags instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tags"
+                ],
+                "summary": "Admin - Update Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tags/{id}/merge": {
+            "post": {
+                "description": "Move every post of the source tags to this tag and delete the sources, in one transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tags"
+                ],
+                "summary": "Admin - Merge Tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to merge into the target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.MergeTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/update-email": {
+            "put": {
+                "description": "Update the authenticated admin's email address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Update Email",
+                "parameters": [
+                    {
+                        "description": "New Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.UpdateEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/update-password": {
+            "put": {
+                "description": "Update the authenticated admin's password. The password must satisfy the password policy and differ from recent passwords. Every other session of the user is logged out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Auth"
+                ],
+                "summary": "Admin - Update Password",
+                "parameters": [
+                    {
+                        "description": "New Password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.UpdatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Retrieve all admin accounts with their roles",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "Admin - Get All Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create an account with a role (owner, editor or viewer). The temporary password is returned only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "Admin - Invite User",
+                "parameters": [
+                    {
+                        "description": "User Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.InviteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/users/{id}": {
+            "delete": {
+                "description": "Delete another user's account. The last owner cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "Admin - Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "description": "Change another user's role. The last owner cannot be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "Admin - Update User Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/public/contact": {
+            "post": {
+                "description": "Send a contact message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Contact"
+                ],
+                "summary": "Public - Send Contact Message",
+                "parameters": [
+                    {
+                        "description": "Message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contact.CreateMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contact.ContactMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/experiences": {
+            "get": {
+                "description": "Retrieve a list of all experiences",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Experiences"
+                ],
+                "summary": "Public - Get All Experiences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/experiences.Experience"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/posts": {
+            "get": {
+                "description": "Retrieve a list of all published posts, respecting scheduled publication and withdrawal. Posts in a series carry their part number and the previous and next parts in Series.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Posts"
+                ],
+                "summary": "Public - Get All Posts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/posts.Post"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/posts/{slug}": {
+            "get": {
+                "description": "Retrieve a published post by its slug. A post in a series carries \"part N of M\" and the previous and next parts in Series. A slug the post had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Posts"
+                ],
+                "summary": "Public - Get Post by Slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.Post"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/profile": {
+            "get": {
+                "description": "Retrieve the user profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Profile"
+                ],
+                "summary": "Public - Get Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/profiles.Profile"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/projects": {
+            "get": {
+                "description": "Retrieve a list of all projects inside their visibility window",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Projects"
+                ],
+                "summary": "Public - Get All Projects",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/projects.Project"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/projects/slug/{slug}": {
+            "get": {
+                "description": "Retrieve a visible project by its slug. A slug the project had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Projects"
+                ],
+                "summary": "Public - Get Project by Slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/projects.Project"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/search": {
+            "get": {
+                "description": "Full-text search across published posts, visible projects and experiences, best matches first. Every word must match, as a prefix, so partial input already finds results. Snippets are HTML-escaped with matches wrapped in \u003cmark\u003e. When nothing matches, \"suggestion\" may hold a corrected query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Search"
+                ],
+                "summary": "Public - Search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/series/{slug}": {
+            "get": {
+                "description": "A series and its published posts in reading order, numbered from 1. A series without published posts is not found. A slug the series had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Series"
+                ],
+                "summary": "Public - Get Series by Slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/skills": {
+            "get": {
+                "description": "Retrieve a list of all skills",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Skills"
+                ],
+                "summary": "Public - Get All Skills",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/skills.Skill"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/tags": {
+            "get": {
+                "description": "Tags of published posts with the number of published posts using each, most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Tags"
+                ],
+                "summary": "Public - Get All Tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/public/tags/{slug}/posts": {
+            "get": {
+                "description": "A tag and its published posts, newest first. A tag without published posts is not found. A slug the tag had before it was renamed answers 301 with the current slug in data.slug and the canonical URL in the Location header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public - Tags"
+                ],
+                "summary": "Public - Get Posts by Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "RFC 3339, optional",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.FinishPasskeyRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "ceremony_token",
+                "credential"
+            ],
+            "properties": {
+                "ceremony_token": {
+                    "type": "string"
+                },
+                "credential": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "auth.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.InviteUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "editor",
+                        "viewer"
+                    ]
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LoginThrottle": {
+            "type": "object",
+            "properties": {
+                "blockedUntil": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "lastFailureAt": {
+                    "type": "string"
+                },
+                "locked": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "auth.MagicLinkRequestBody": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.MagicLinkVerifyRequest": {
+            "type": "object",
+            "required": [
+                "nonce",
+                "token"
+            ],
+            "properties": {
+                "nonce": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.OIDCCallbackRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.PasskeyLoginFinishRequest": {
+            "type": "object",
+            "required": [
+                "ceremony_token",
+                "credential"
+            ],
+            "properties": {
+                "ceremony_token": {
+                    "type": "string"
+                },
+                "credential": {
+                    "type": "object"
+                }
+            }
+        },
+        "auth.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TOTPCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
                 }
             }
         },
-        "/public/skills": {
-            "get": {
-                "description": "Retrieve a list of all skills",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Public - Skills"
-                ],
-                "summary": "Public - Get All Skills",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/skills.Skill"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "auth.LoginRequest": {
+        "auth.TwoFactorLoginRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "challenge_token",
+                "code"
             ],
             "properties": {
-                "email": {
+                "challenge_token": {
                     "type": "string"
                 },
-                "password": {
+                "code": {
                     "type": "string"
                 }
             }
@@ -1524,8 +6249,23 @@ const docTemplate = `{
             ],
             "properties": {
                 "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.UpdateUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
                     "type": "string",
-                    "minLength": 6
+                    "enum": [
+                        "owner",
+                        "editor",
+                        "viewer"
+                    ]
                 }
             }
         },
@@ -1725,6 +6465,26 @@ const docTemplate = `{
                 }
             }
         },
+        "markdown.Heading": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/markdown.Heading"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
         "pagination.Meta": {
             "type": "object",
             "properties": {
@@ -1766,6 +6526,14 @@ const docTemplate = `{
                 "is_published": {
                     "type": "boolean"
                 },
+                "publish_at": {
+                    "description": "publish later; overrides is_published",
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "custom slug, pinned against title changes",
+                    "type": "string"
+                },
                 "summary": {
                     "type": "string"
                 },
@@ -1775,6 +6543,21 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "title": {
+                    "type": "string"
+                },
+                "unpublish_at": {
+                    "description": "withdraw automatically",
+                    "type": "string"
+                }
+            }
+        },
+        "posts.PartLink": {
+            "type": "object",
+            "properties": {
+                "slug": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 }
@@ -1783,6 +6566,10 @@ const docTemplate = `{
         "posts.Post": {
             "type": "object",
             "properties": {
+                "contentHTML": {
+                    "description": "rendered from ContentMarkdown on save",
+                    "type": "string"
+                },
                 "contentMarkdown": {
                     "type": "string"
                 },
@@ -1801,15 +6588,41 @@ const docTemplate = `{
                 "isPublished": {
                     "type": "boolean"
                 },
+                "publishAt": {
+                    "description": "scheduled publication, cleared once done",
+                    "type": "string"
+                },
                 "publishedAt": {
                     "type": "string"
                 },
+                "readingTime": {
+                    "description": "minutes",
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "set on public responses for posts in a series",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/posts.SeriesPart"
+                        }
+                    ]
+                },
                 "slug": {
                     "type": "string"
                 },
+                "slugPinned": {
+                    "description": "custom slug, kept when the title changes",
+                    "type": "boolean"
+                },
                 "summary": {
                     "type": "string"
                 },
+                "tableOfContents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/markdown.Heading"
+                    }
+                },
                 "tags": {
                     "type": "array",
                     "items": {
@@ -1819,14 +6632,50 @@ const docTemplate = `{
                 "title": {
                     "type": "string"
                 },
+                "unpublishAt": {
+                    "description": "scheduled withdrawal, cleared once done",
+                    "type": "string"
+                },
                 "updatedAt": {
                     "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.SeriesPart": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "next": {
+                    "$ref": "#/definitions/posts.PartLink"
+                },
+                "part": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "$ref": "#/definitions/posts.PartLink"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
         "posts.Tag": {
             "type": "object",
             "properties": {
+                "description": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1853,6 +6702,14 @@ const docTemplate = `{
                 "is_published": {
                     "type": "boolean"
                 },
+                "publish_at": {
+                    "description": "publish later; overrides is_published",
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "custom slug, pinned; \"\" unpins it, omitted keeps it",
+                    "type": "string"
+                },
                 "summary": {
                     "type": "string"
                 },
@@ -1864,6 +6721,10 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
+                },
+                "unpublish_at": {
+                    "description": "withdraw automatically",
+                    "type": "string"
                 }
             }
         },
@@ -2008,18 +6869,34 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "slug": {
+                    "description": "custom slug, pinned against title changes",
+                    "type": "string"
+                },
                 "start_date": {
                     "description": "YYYY-MM-DD",
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                },
+                "visible_from": {
+                    "description": "hide before this time",
+                    "type": "string"
+                },
+                "visible_until": {
+                    "description": "hide from this time on",
+                    "type": "string"
                 }
             }
         },
         "projects.Project": {
             "type": "object",
             "properties": {
+                "contentHTML": {
+                    "description": "rendered from ContentMarkdown on save",
+                    "type": "string"
+                },
                 "contentMarkdown": {
                     "type": "string"
                 },
@@ -2050,6 +6927,10 @@ const docTemplate = `{
                 "isFeatured": {
                     "type": "boolean"
                 },
+                "readingTime": {
+                    "description": "minutes",
+                    "type": "integer"
+                },
                 "repoURL": {
                     "type": "string"
                 },
@@ -2062,14 +6943,35 @@ const docTemplate = `{
                 "slug": {
                     "type": "string"
                 },
+                "slugPinned": {
+                    "description": "custom slug, kept when the title changes",
+                    "type": "boolean"
+                },
                 "startDate": {
                     "type": "string"
                 },
+                "tableOfContents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/markdown.Heading"
+                    }
+                },
                 "title": {
                     "type": "string"
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "visibleFrom": {
+                    "description": "hidden from the public before this time",
+                    "type": "string"
+                },
+                "visibleUntil": {
+                    "description": "hidden from the public from this time on",
+                    "type": "string"
+                },
+                "wordCount": {
+                    "type": "integer"
                 }
             }
         },
@@ -2109,9 +7011,76 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "slug": {
+                    "description": "custom slug, pinned; \"\" unpins it, omitted keeps it",
+                    "type": "string"
+                },
                 "start_date": {
                     "type": "string"
                 },
+                "title": {
+                    "type": "string"
+                },
+                "visible_from": {
+                    "description": "hide before this time",
+                    "type": "string"
+                },
+                "visible_until": {
+                    "description": "hide from this time on",
+                    "type": "string"
+                }
+            }
+        },
+        "series.CreateSeriesRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "cover_url": {
+                    "description": "file_path of an uploaded image, or any URL",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "custom slug; empty to generate one from the title",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "series.SetPostsRequest": {
+            "type": "object",
+            "properties": {
+                "post_ids": {
+                    "description": "every post of the series, in reading order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "series.UpdateSeriesRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "cover_url": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "custom slug; empty to generate one from the title",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 }
@@ -2165,6 +7134,35 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "tags.MergeTagsRequest": {
+            "type": "object",
+            "required": [
+                "source_ids"
+            ],
+            "properties": {
+                "source_ids": {
+                    "description": "tags merged into this one, then deleted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "tags.UpdateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"log"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +14,18 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
+	Mail     MailConfig
+	OIDC     OIDCConfig
+	WebAuthn WebAuthnConfig
+	Password PasswordConfig
+	Audit    AuditConfig
+	Network  NetworkConfig
+	Schedule ScheduleConfig
+	Site     SiteConfig
+	Feed     FeedConfig
+	SEO      SEOConfig
+	Slug     SlugConfig
 }
 
 type ServerConfig struct {
@@ -29,20 +44,151 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string
-	Expiration int // hours
+	Secret            string // HS256 secret, only used when KeysDir is empty
+	Expiration        int    // minutes, access token lifetime
+	RefreshExpiration int    // hours
+	KeysDir           string // directory of PEM signing keys named <kid>.pem
+	GenerateKeys      bool   // create a key when KeysDir has none; never in release mode
+	Algorithm         string // RS256 or EdDSA, used when generating a key
+	ActiveKeyID       string // signing key; defaults to the newest kid
+	Issuer            string
+	Audience          string
 }
 
+type AuthConfig struct {
+	TOTPIssuer         string
+	MaxLoginAttempts   int // failures before a temporary lockout
+	LoginBackoffBase   int // seconds, doubled after every failure
+	LockoutDuration    int // minutes, doubled for each lockout in a row
+	LoginAttemptWindow int // minutes without failures before the counter resets
+	PasswordResetURL   string
+	PasswordResetTTL   int // minutes
+	MagicLinkURL       string
+	MagicLinkTTL       int    // minutes
+	MagicLinkSecret    string // signs magic links; required in release mode, defaults to JWT_SECRET otherwise
+	EmailRequestLimit  int    // emails one address or IP may trigger per window, 0 disables the limit
+	EmailRequestWindow int    // minutes
+}
+
+type MailConfig struct {
+	Driver   string // smtp, file or log
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	FileDir  string
+}
+
+// OIDCConfig enables login through an external OpenID Connect provider.
+// It is disabled while Issuer is empty.
+type OIDCConfig struct {
+	Issuer          string
+	ClientID        string
+	ClientSecret    string // optional for public clients, PKCE is always used
+	RedirectURL     string // frontend page that posts code and state back
+	Scopes          string // space-separated
+	AllowedSubjects string // comma-separated
+	AllowedEmails   string // comma-separated
+}
+
+// WebAuthnConfig identifies this site as the relying party for passkeys.
+type WebAuthnConfig struct {
+	RPID          string // domain of the admin frontend, without scheme or port
+	RPDisplayName string
+	RPOrigins     string // comma-separated origins allowed to run the ceremonies
+}
+
+type PasswordConfig struct {
+	MinLength         int
+	RequireUpper      bool
+	RequireLower      bool
+	RequireDigit      bool
+	RequireSymbol     bool
+	HistorySize       int    // number of recent passwords that cannot be reused
+	BreachedListPath  string // optional file or directory of SHA-1 hashes
+	Algorithm         string // argon2id or bcrypt, for new hashes
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+}
+
+type AuditConfig struct {
+	RetentionDays int // 0 keeps entries forever
+}
+
+type ScheduleConfig struct {
+	Interval int // seconds between scheduled publishing runs, 0 disables them
+}
+
+// SiteConfig describes the public frontend that published content links to.
+type SiteConfig struct {
+	URL         string // frontend origin, e.g. https://example.com
+	Title       string
+	Description string
+	Author      string
+	PostPath    string // path of a post page, {slug} is replaced by the slug
+	ProjectPath string // path of a project page, {slug} or {id} is replaced
+}
+
+// PostURL returns the absolute URL of a post page.
+func (s SiteConfig) PostURL(slug string) string {
+	return s.pageURL(s.PostPath, "", slug)
+}
+
+// ProjectURL returns the absolute URL of a project page.
+func (s SiteConfig) ProjectURL(id, slug string) string {
+	return s.pageURL(s.ProjectPath, id, slug)
+}
+
+func (s SiteConfig) pageURL(path, id, slug string) string {
+	path = strings.NewReplacer("{id}", url.PathEscape(id), "{slug}", url.PathEscape(slug)).Replace(path)
+	return strings.TrimRight(s.URL, "/") + path
+}
+
+type FeedConfig struct {
+	Limit       int  // most recent posts per feed
+	FullContent bool // full HTML instead of the summary, unless ?content= says otherwise
+}
+
+type SlugConfig struct {
+	ReservedWords string // comma-separated slugs to refuse, on top of the built-in ones
+}
+
+// SEOConfig controls the sitemap, robots.txt and IndexNow notifications.
+type SEOConfig struct {
+	SitemapMaxURLs      int    // URLs per sitemap; more are split behind a sitemap index
+	RobotsDisallow      string // comma-separated paths crawlers are asked to skip
+	AllowIndexing       bool   // false disallows everything, e.g. on staging
+	IndexNowEndpoint    string // e.g. https://api.indexnow.org/indexnow, empty disables notifications
+	IndexNowKey         string
+	IndexNowKeyLocation string // URL of the key file, defaults to SITE_URL/indexnow.txt
+}
+
+// NetworkConfig restricts where the protected admin API can be used from.
+// All lists are comma-separated IP addresses or CIDR ranges.
+type NetworkConfig struct {
+	TrustedProxies string // proxies whose X-Forwarded-For is believed
+	AdminAllow     string // empty allows every network
+	AdminDeny      string // checked before the allow list
+	SensitiveAllow string // if set, sensitive routes need one of these networks
+}
+
+// defaultJWTSecret is the public placeholder for JWT_SECRET.
+const defaultJWTSecret = "change_this_secret_in_production"
+
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists (won't error if missing)
 	if err := godotenv.Load(); err != nil {
 		log.Println(".env file not found, using environment variables")
 	}
 
+	mode := getEnv("SERVER_MODE", "debug")
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:    getEnv("SERVER_PORT", "8080"),
-			Mode:    getEnv("SERVER_MODE", "debug"),
+			Mode:    mode,
 			BaseURL: getEnv("SERVER_BASE_URL", "http://localhost:8080"),
 		},
 		Database: DatabaseConfig{
@@ -54,14 +200,132 @@ func LoadConfig() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "change_this_secret_in_production"),
-			Expiration: getEnvAsInt("JWT_EXPIRATION", 24),
+			Secret:            getEnv("JWT_SECRET", defaultJWTSecret),
+			Expiration:        getEnvAsInt("JWT_EXPIRATION_MINUTES", 15),
+			RefreshExpiration: getEnvAsInt("JWT_REFRESH_EXPIRATION_HOURS", 720),
+			KeysDir:           getEnv("JWT_KEYS_DIR", "keys"),
+			GenerateKeys:      mode != "release",
+			Algorithm:         getEnv("JWT_ALGORITHM", "EdDSA"),
+			ActiveKeyID:       getEnv("JWT_ACTIVE_KID", ""),
+			Issuer:            getEnv("JWT_ISSUER", "http://localhost:8080"),
+			Audience:          getEnv("JWT_AUDIENCE", "personal-admin"),
+		},
+		Auth: AuthConfig{
+			TOTPIssuer:         getEnv("TOTP_ISSUER", "Personal Website"),
+			MaxLoginAttempts:   getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+			LoginBackoffBase:   getEnvAsInt("LOGIN_BACKOFF_BASE_SECONDS", 1),
+			LockoutDuration:    getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+			LoginAttemptWindow: getEnvAsInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 60),
+			PasswordResetURL:   getEnv("PASSWORD_RESET_URL", "http://localhost:3000/admin/reset-password"),
+			PasswordResetTTL:   getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 30),
+			MagicLinkURL:       getEnv("MAGIC_LINK_URL", "http://localhost:3000/admin/magic-link"),
+			MagicLinkTTL:       getEnvAsInt("MAGIC_LINK_TTL_MINUTES", 15),
+			MagicLinkSecret:    getEnv("MAGIC_LINK_SECRET", ""),
+			EmailRequestLimit:  getEnvAsInt("AUTH_EMAIL_REQUEST_LIMIT", 5),
+			EmailRequestWindow: getEnvAsInt("AUTH_EMAIL_REQUEST_WINDOW_MINUTES", 60),
+		},
+		Mail: MailConfig{
+			Driver:   getEnv("MAIL_DRIVER", "log"),
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "1025"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("MAIL_FROM", "no-reply@localhost"),
+			FileDir:  getEnv("MAIL_FILE_DIR", "mail"),
+		},
+		OIDC: OIDCConfig{
+			Issuer:          getEnv("OIDC_ISSUER", ""),
+			ClientID:        getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:    getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:     getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/admin/oidc/callback"),
+			Scopes:          getEnv("OIDC_SCOPES", "openid email profile"),
+			AllowedSubjects: getEnv("OIDC_ALLOWED_SUBJECTS", ""),
+			AllowedEmails:   getEnv("OIDC_ALLOWED_EMAILS", ""),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:          getEnv("WEBAUTHN_RP_ID", "localhost"),
+			RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "Personal Website"),
+			RPOrigins:     getEnv("WEBAUTHN_RP_ORIGINS", "http://localhost:3000"),
+		},
+		Password: PasswordConfig{
+			MinLength:         getEnvAsInt("PASSWORD_MIN_LENGTH", 12),
+			RequireUpper:      getEnvAsBool("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:      getEnvAsBool("PASSWORD_REQUIRE_LOWER", true),
+			RequireDigit:      getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:     getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			HistorySize:       getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+			BreachedListPath:  getEnv("PASSWORD_BREACHED_LIST", ""),
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvAsInt("BCRYPT_COST", 12),
+			Argon2Memory:      getEnvAsInt("ARGON2_MEMORY_KB", 64*1024),
+			Argon2Iterations:  getEnvAsInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvAsInt("ARGON2_PARALLELISM", 2),
+		},
+		Audit: AuditConfig{
+			RetentionDays: getEnvAsInt("AUDIT_RETENTION_DAYS", 365),
+		},
+		Schedule: ScheduleConfig{
+			Interval: getEnvAsInt("SCHEDULE_INTERVAL_SECONDS", 60),
+		},
+		Site: SiteConfig{
+			URL:         getEnv("SITE_URL", "http://localhost:3000"),
+			Title:       getEnv("SITE_TITLE", "Personal Website"),
+			Description: getEnv("SITE_DESCRIPTION", ""),
+			Author:      getEnv("SITE_AUTHOR", ""),
+			PostPath:    getEnv("SITE_POST_PATH", "/blog/{slug}"),
+			ProjectPath: getEnv("SITE_PROJECT_PATH", "/projects/{slug}"),
+		},
+		Feed: FeedConfig{
+			Limit:       getEnvAsInt("FEED_ITEM_LIMIT", 20),
+			FullContent: getEnvAsBool("FEED_FULL_CONTENT", true),
+		},
+		Slug: SlugConfig{
+			ReservedWords: getEnv("SLUG_RESERVED_WORDS", ""),
+		},
+		SEO: SEOConfig{
+			SitemapMaxURLs:      getEnvAsInt("SITEMAP_MAX_URLS", 50000),
+			RobotsDisallow:      getEnv("ROBOTS_DISALLOW", ""),
+			AllowIndexing:       getEnvAsBool("ROBOTS_ALLOW_INDEXING", true),
+			IndexNowEndpoint:    getEnv("INDEXNOW_ENDPOINT", ""),
+			IndexNowKey:         getEnv("INDEXNOW_KEY", ""),
+			IndexNowKeyLocation: getEnv("INDEXNOW_KEY_LOCATION", ""),
+		},
+		Network: NetworkConfig{
+			TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+			AdminAllow:     getEnv("ADMIN_ALLOW_CIDRS", ""),
+			AdminDeny:      getEnv("ADMIN_DENY_CIDRS", ""),
+			SensitiveAllow: getEnv("ADMIN_SENSITIVE_ALLOW_CIDRS", ""),
 		},
 	}
 
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// validate refuses settings that are fine for development but unsafe in
+// release mode.
+func (c *Config) validate() error {
+	if c.Server.Mode != "release" {
+		return nil
+	}
+	if c.Mail.Driver != "smtp" && c.Mail.Driver != "file" {
+		// the log driver would write password reset links, which are live
+		// credentials, into the server log
+		return errors.New("MAIL_DRIVER must be smtp or file in release mode")
+	}
+	if c.JWT.KeysDir == "" && c.JWT.Secret == defaultJWTSecret {
+		return errors.New("JWT_SECRET must be changed in release mode when JWT_KEYS_DIR is empty")
+	}
+	if c.Auth.MagicLinkSecret == "" {
+		// without it magic links would be signed with the JWT secret, or with
+		// nothing but the default when signing keys are used
+		return errors.New("MAGIC_LINK_SECRET must be set in release mode")
+	}
+	return nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -83,3 +347,15 @@ func getEnvAsInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvAsBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		switch value {
+		case "true", "1", "yes":
+			return true
+		case "false", "0", "no":
+			return false
+		}
+	}
+	return fallback
+}
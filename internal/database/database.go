@@ -30,6 +30,8 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 			Logger: logger.Default.LogMode(logger.Error),
 		}
 	}
+	// Report constraint violations as gorm errors, e.g. gorm.ErrDuplicatedKey
+	gormConfig.TranslateError = true
 
 	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
@@ -3,6 +3,7 @@ package database
 import (
 	"log"
 
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/modules/auth"
 	"github.com/prakoso-id/personal-backend/internal/modules/contact"
 	"github.com/prakoso-id/personal-backend/internal/modules/experiences"
@@ -10,15 +11,36 @@ import (
 	"github.com/prakoso-id/personal-backend/internal/modules/posts"
 	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
 	"github.com/prakoso-id/personal-backend/internal/modules/projects"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
+	"github.com/prakoso-id/personal-backend/internal/modules/series"
 	"github.com/prakoso-id/personal-backend/internal/modules/skills"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"gorm.io/gorm"
 )
 
 func Migrate(db *gorm.DB) {
 	log.Println("Migrating database...")
 
+	// Accounts created before roles existed ran the site. Adding the column
+	// gives them the default role, the least access, so they are made owners
+	// again; later accounts always name their role.
+	hadRoles := db.Migrator().HasColumn(&auth.User{}, "Role")
+
 	err := db.AutoMigrate(
 		&auth.User{},
+		&auth.RefreshToken{},
+		&auth.RecoveryCode{},
+		&auth.LoginChallenge{},
+		&auth.LoginThrottle{},
+		&auth.PasswordResetToken{},
+		&auth.MagicLinkToken{},
+		&auth.APIKey{},
+		&auth.Session{},
+		&auth.OIDCLoginState{},
+		&auth.Passkey{},
+		&auth.PasskeyCeremony{},
+		&auth.PasswordHistory{},
 		&profiles.Profile{},
 		&profiles.SocialLink{},
 		&skills.Skill{},
@@ -28,11 +50,34 @@ func Migrate(db *gorm.DB) {
 		&posts.Tag{},
 		&contact.ContactMessage{},
 		&images.Image{},
+		&audit.AuditLog{},
+		&revisions.Revision{},
+		&revisions.Draft{},
+		&slugs.History{},
+		&series.Series{},
+		&series.Membership{},
 	)
 
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	if !hadRoles {
+		if err := db.Exec("UPDATE users SET role = ?", auth.RoleOwner).Error; err != nil {
+			log.Fatalf("Failed to assign roles to existing users: %v", err)
+		}
+	}
+
+	if err := search.Migrate(db); err != nil {
+		log.Fatalf("Failed to migrate search index: %v", err)
+	}
+
+	if err := posts.RenderMissing(db); err != nil {
+		log.Fatalf("Failed to render posts: %v", err)
+	}
+	if err := projects.RenderMissing(db); err != nil {
+		log.Fatalf("Failed to render projects: %v", err)
+	}
+
 	log.Println("Database migrated successfully")
 }
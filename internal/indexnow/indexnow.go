@@ -0,0 +1,149 @@
+package indexnow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+// maxURLs is the most URLs the protocol accepts in one submission.
+const maxURLs = 10000
+
+// Notifier tells search engines that public pages were added, changed or
+// removed, so they are crawled again soon.
+type Notifier interface {
+	// Notify submits URLs in the background. Failures are logged, never
+	// returned: a missed notification only delays crawling.
+	Notify(urls ...string)
+}
+
+// New returns a client for INDEXNOW_ENDPOINT, or a notifier that does
+// nothing when no endpoint or key is configured.
+func New(cfg *config.Config) Notifier {
+	if cfg.SEO.IndexNowEndpoint == "" || cfg.SEO.IndexNowKey == "" {
+		return noop{}
+	}
+
+	keyLocation := cfg.SEO.IndexNowKeyLocation
+	if keyLocation == "" {
+		keyLocation = strings.TrimRight(cfg.Site.URL, "/") + "/indexnow.txt"
+	}
+	host := ""
+	if u, err := url.Parse(cfg.Site.URL); err == nil {
+		host = u.Host
+	}
+	return NewClient(cfg.SEO.IndexNowEndpoint, cfg.SEO.IndexNowKey, keyLocation, host)
+}
+
+type noop struct{}
+
+func (noop) Notify(urls ...string) {}
+
+// Client submits URLs to an IndexNow endpoint, such as
+// https://api.indexnow.org/indexnow or a local stand-in.
+type Client struct {
+	endpoint    string
+	key         string
+	keyLocation string
+	host        string // host of the submitted URLs
+	http        *http.Client
+}
+
+func NewClient(endpoint, key, keyLocation, host string) *Client {
+	return &Client{
+		endpoint:    endpoint,
+		key:         key,
+		keyLocation: keyLocation,
+		host:        host,
+		http:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) Notify(urls ...string) {
+	if len(urls) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := c.Submit(ctx, urls); err != nil {
+			log.Printf("indexnow: failed to submit %d URL(s): %v", len(urls), err)
+		}
+	}()
+}
+
+type submission struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation,omitempty"`
+	URLList     []string `json:"urlList"`
+}
+
+// Submit posts URLs to the endpoint and waits for the answer. Duplicates
+// are sent once; more than the protocol allows per request are sent in
+// batches.
+func (c *Client) Submit(ctx context.Context, urls []string) error {
+	urls = unique(urls)
+	for start := 0; start < len(urls); start += maxURLs {
+		end := start + maxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		if err := c.submit(ctx, urls[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) submit(ctx context.Context, urls []string) error {
+	body, err := json.Marshal(submission{
+		Host:        c.host,
+		Key:         c.key,
+		KeyLocation: c.keyLocation,
+		URLList:     urls,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	// 200 means submitted, 202 that the key is still being validated
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func unique(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	result := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			result = append(result, u)
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package indexnow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+// testEndpoint records the submissions posted to it.
+func testEndpoint(t *testing.T, status int) (*httptest.Server, <-chan submission) {
+	t.Helper()
+	received := make(chan submission, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json; charset=utf-8" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		var body submission
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode submission: %v", err)
+		}
+		received <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestSubmit(t *testing.T) {
+	server, received := testEndpoint(t, http.StatusOK)
+	client := NewClient(server.URL, "test-key", "https://example.com/indexnow.txt", "example.com")
+
+	urls := []string{
+		"https://example.com/blog/hello",
+		"https://example.com/blog/hello",
+		"https://example.com/projects/site",
+	}
+	if err := client.Submit(context.Background(), urls); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	want := submission{
+		Host:        "example.com",
+		Key:         "test-key",
+		KeyLocation: "https://example.com/indexnow.txt",
+		URLList:     []string{"https://example.com/blog/hello", "https://example.com/projects/site"},
+	}
+	if got := <-received; !reflect.DeepEqual(got, want) {
+		t.Errorf("submission = %+v, want %+v", got, want)
+	}
+}
+
+func TestSubmitRejected(t *testing.T) {
+	server, _ := testEndpoint(t, http.StatusForbidden)
+	client := NewClient(server.URL, "wrong-key", "", "example.com")
+
+	if err := client.Submit(context.Background(), []string{"https://example.com/"}); err == nil {
+		t.Fatal("Submit succeeded, want an error for 403")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	server, received := testEndpoint(t, http.StatusAccepted)
+	notifier := New(&config.Config{
+		Site: config.SiteConfig{URL: "https://example.com"},
+		SEO:  config.SEOConfig{IndexNowEndpoint: server.URL, IndexNowKey: "test-key"},
+	})
+
+	notifier.Notify()
+	notifier.Notify("https://example.com/blog/hello")
+
+	select {
+	case got := <-received:
+		want := submission{
+			Host:        "example.com",
+			Key:         "test-key",
+			KeyLocation: "https://example.com/indexnow.txt",
+			URLList:     []string{"https://example.com/blog/hello"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("submission = %+v, want %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no submission received")
+	}
+
+	// the empty Notify must not have sent a request of its own
+	select {
+	case got := <-received:
+		t.Errorf("unexpected submission %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNewUnconfigured(t *testing.T) {
+	notifier := New(&config.Config{Site: config.SiteConfig{URL: "https://example.com"}})
+	if _, ok := notifier.(noop); !ok {
+		t.Errorf("New = %T, want a no-op notifier without an endpoint", notifier)
+	}
+}
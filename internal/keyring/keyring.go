@@ -0,0 +1,347 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+const (
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+	rsaKeyBits       = 2048
+)
+
+// Key is one entry of the keyring. Keys loaded from <kid>.pub.pem have no
+// private part and can only verify tokens.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// Keyring signs access tokens with its active key and verifies tokens signed
+// by any key it holds, so that old tokens stay valid while keys are rotated.
+type Keyring struct {
+	active   *Key
+	keys     map[string]*Key
+	methods  []string
+	issuer   string
+	audience string
+}
+
+// JWK is the public part of a key as published at /.well-known/jwks.json.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Load reads every key in JWT_KEYS_DIR. Private keys are stored as PKCS#8 PEM
+// files named <kid>.pem; retired keys kept only for verification may be stored
+// as <kid>.pub.pem. When the directory holds no private key a new one is
+// generated, except in release mode: a key generated on a fresh container
+// would change with every deploy, logging everyone out and breaking JWKS
+// consumers. With an empty JWT_KEYS_DIR tokens are signed HS256 with JWT_SECRET.
+func Load(cfg config.JWTConfig) (*Keyring, error) {
+	k := &Keyring{
+		keys:     map[string]*Key{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	if cfg.KeysDir == "" {
+		secret := []byte(cfg.Secret)
+		k.add(&Key{Method: jwt.SigningMethodHS256, private: secret, public: secret})
+		k.active = k.keys[""]
+		return k, nil
+	}
+
+	if err := os.MkdirAll(cfg.KeysDir, 0700); err != nil {
+		return nil, err
+	}
+	if err := k.loadDir(cfg.KeysDir); err != nil {
+		return nil, err
+	}
+
+	if len(k.signingKeyIDs()) == 0 {
+		if !cfg.GenerateKeys {
+			return nil, fmt.Errorf("no JWT signing key in %s; create one with `admin jwt rotate` on persistent storage", cfg.KeysDir)
+		}
+		kid, err := Generate(cfg.KeysDir, cfg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Generated JWT signing key %s in %s", kid, cfg.KeysDir)
+		if err := k.loadDir(cfg.KeysDir); err != nil {
+			return nil, err
+		}
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" {
+		ids := k.signingKeyIDs()
+		activeID = ids[len(ids)-1]
+	}
+	active, ok := k.keys[activeID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("JWT_ACTIVE_KID %q has no private key in %s", activeID, cfg.KeysDir)
+	}
+	k.active = active
+
+	return k, nil
+}
+
+func (k *Keyring) loadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, privateKeySuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		var key *Key
+		if strings.HasSuffix(name, publicKeySuffix) {
+			key, err = parsePublicKey(strings.TrimSuffix(name, publicKeySuffix), data)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, privateKeySuffix), data)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		// A private key wins over a public-only copy of the same kid
+		if existing, ok := k.keys[key.ID]; ok && existing.private != nil {
+			continue
+		}
+		k.add(key)
+	}
+	return nil
+}
+
+func (k *Keyring) add(key *Key) {
+	k.keys[key.ID] = key
+	for _, m := range k.methods {
+		if m == key.Method.Alg() {
+			return
+		}
+	}
+	k.methods = append(k.methods, key.Method.Alg())
+}
+
+// signingKeyIDs returns the kids of keys that can sign, oldest first.
+func (k *Keyring) signingKeyIDs() []string {
+	var ids []string
+	for id, key := range k.keys {
+		if key.private != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ActiveKeyID returns the kid new tokens are signed with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active.ID
+}
+
+// Sign adds the issuer and audience to claims and signs them with the active key.
+func (k *Keyring) Sign(claims jwt.MapClaims) (string, error) {
+	if k.issuer != "" {
+		claims["iss"] = k.issuer
+	}
+	if k.audience != "" {
+		claims["aud"] = k.audience
+	}
+
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	if k.active.ID != "" {
+		token.Header["kid"] = k.active.ID
+	}
+	return token.SignedString(k.active.private)
+}
+
+// Parse verifies the signature, expiry, issuer and audience of a token and
+// returns its claims. The key is selected by the kid header.
+func (k *Keyring) Parse(tokenString string) (jwt.MapClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(k.methods),
+		jwt.WithExpirationRequired(),
+	}
+	if k.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(k.issuer))
+	}
+	if k.audience != "" {
+		opts = append(opts, jwt.WithAudience(k.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.public, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// JWKS returns the public keys for verification by other services. HS256
+// secrets are never published.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		key := k.keys[id]
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Kid: key.ID,
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Kid: key.ID,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+// Generate writes a new private key for algorithm ("RS256" or "EdDSA") into
+// dir and returns its kid. Kids start with a UTC timestamp, so the new key
+// becomes the active one unless JWT_ACTIVE_KID says otherwise.
+func Generate(dir, algorithm string) (string, error) {
+	var private crypto.PrivateKey
+	switch algorithm {
+	case "RS256":
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return "", err
+		}
+		private = key
+	case "EdDSA":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		private = key
+	default:
+		return "", fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	kid := time.Now().UTC().Format("20060102T150405Z") + "-" + strings.ToLower(algorithm)
+	path := filepath.Join(dir, kid+privateKeySuffix)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return kid, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, private: private, public: &private.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, private: private, public: private.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+func parsePublicKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch public := parsed.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, public: public}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, public: public}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
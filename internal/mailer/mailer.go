@@ -0,0 +1,88 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email such as password reset links.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New returns the mailer selected by MAIL_DRIVER: "smtp", "file" or "log" (default).
+func New(cfg *config.Config) Mailer {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return &smtpMailer{cfg: cfg.Mail}
+	case "file":
+		return &fileMailer{from: cfg.Mail.From, dir: cfg.Mail.FileDir}
+	default:
+		return &logMailer{from: cfg.Mail.From}
+	}
+}
+
+type smtpMailer struct {
+	cfg config.MailConfig
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	addr := m.cfg.Host + ":" + m.cfg.Port
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, format(m.cfg.From, msg))
+}
+
+// fileMailer writes every message as an .eml file, handy for local testing.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func (m *fileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, msg), 0o600)
+}
+
+type logMailer struct {
+	from string
+}
+
+func (m *logMailer) Send(msg Message) error {
+	log.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
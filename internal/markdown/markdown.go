@@ -0,0 +1,157 @@
+// Package markdown renders post and project content to sanitized HTML with a
+// table of contents and reading statistics.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// WordsPerMinute is the reading speed used for the reading time.
+const WordsPerMinute = 200
+
+// Heading is an entry of the table of contents. ID is the anchor of the
+// heading in the rendered HTML.
+type Heading struct {
+	Level    int
+	Text     string
+	ID       string
+	Children []*Heading
+}
+
+// TOC is a table of contents: the top-level headings with the lower
+// levels nested under them.
+type TOC []*Heading
+
+// Result is the rendered form of a markdown document.
+type Result struct {
+	HTML        string
+	TOC         TOC
+	WordCount   int
+	ReadingTime int // minutes, rounded up
+}
+
+// Raw HTML is passed through by goldmark and then cleaned by the sanitizer,
+// so authors can still embed the tags the allowlist permits.
+var converter = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		),
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Syntax highlighting uses Chroma's CSS classes
+	p.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).OnElements("pre", "code", "span")
+	p.AllowAttrs("tabindex").Matching(regexp.MustCompile(`^0$`)).OnElements("pre")
+
+	// Task list items
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return p
+}
+
+// Render converts markdown to sanitized HTML and collects its headings and
+// word count. Code blocks are left out of the word count.
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	doc := converter.Parser().Parse(text.NewReader(src))
+
+	var buf bytes.Buffer
+	if err := converter.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		HTML: sanitizer.Sanitize(buf.String()),
+		TOC:  TOC{},
+	}
+
+	var stack []*Heading
+	var words strings.Builder
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			heading := &Heading{Level: node.Level, Text: plainText(node, src)}
+			if id, ok := node.AttributeString("id"); ok {
+				if b, ok := id.([]byte); ok {
+					heading.ID = string(b)
+				}
+			}
+
+			// Attach to the closest preceding heading of a higher level
+			for len(stack) > 0 && stack[len(stack)-1].Level >= heading.Level {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				result.TOC = append(result.TOC, heading)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, heading)
+			}
+			stack = append(stack, heading)
+		case *ast.Text:
+			words.Write(node.Segment.Value(src))
+			words.WriteByte(' ')
+		case *ast.String:
+			words.Write(node.Value)
+			words.WriteByte(' ')
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.WordCount = len(strings.Fields(words.String()))
+	result.ReadingTime = (result.WordCount + WordsPerMinute - 1) / WordsPerMinute
+
+	return result, nil
+}
+
+// plainText returns the text of an inline node without markup.
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
@@ -1,17 +1,38 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+	"github.com/prakoso-id/personal-backend/internal/modules/auth"
 )
 
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// AuthMiddleware accepts either a JWT access token ("Authorization: Bearer <token>")
+// or an API key ("X-API-Key: <key>" or "Authorization: ApiKey <key>").
+func AuthMiddleware(keys *keyring.Keyring, authService auth.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := apiKeyFromRequest(c); apiKey != "" {
+			key, permissions, err := authService.AuthenticateAPIKey(apiKey)
+			if err != nil {
+				if errors.Is(err, auth.ErrInvalidAPIKey) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+					return
+				}
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+				return
+			}
+
+			c.Set("user_id", key.UserID.String())
+			c.Set("api_key_id", key.ID.String())
+			c.Set("permissions", permissions)
+			c.Next()
+			return
+		}
+
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
@@ -23,25 +44,76 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(cfg.JWT.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Checks signature, kid, expiry, issuer and audience
+		claims, err := keys.Parse(tokenString)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user_id", claims["sub"])
-		} else {
+		// Every access token belongs to a login session that can be revoked
+		sub, _ := claims["sub"].(string)
+		sid, _ := claims["sid"].(string)
+		userID, errUser := uuid.Parse(sub)
+		sessionID, errSession := uuid.Parse(sid)
+		if errUser != nil || errSession != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			return
 		}
+		if err := authService.ValidateSession(userID, sessionID); err != nil {
+			if errors.Is(err, auth.ErrInvalidSession) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify session"})
+			return
+		}
+
+		role, _ := claims["role"].(string)
+		c.Set("user_id", sub)
+		c.Set("session_id", sid)
+		c.Set("role", role)
+		c.Set("permissions", auth.PermissionsForRole(role))
+
+		c.Next()
+	}
+}
 
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "ApiKey ") {
+		return strings.TrimPrefix(header, "ApiKey ")
+	}
+	return ""
+}
+
+// RequirePermission aborts with 403 unless the authenticated caller has been
+// granted permission. It must run after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, _ := c.Get("permissions")
+		granted, _ := permissions.([]string)
+		for _, p := range granted {
+			if p == permission {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// RequireUserToken rejects API key authentication, for routes that act on the
+// caller's own account such as changing the password.
+func RequireUserToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint requires a user login"})
+			return
+		}
 		c.Next()
 	}
 }
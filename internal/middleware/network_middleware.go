@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/netpolicy"
+)
+
+// NetworkPolicy aborts with 403 when the client IP is on the deny list or
+// missing from a configured allow list. The client IP is taken from
+// X-Forwarded-For only when the request came through a trusted proxy.
+func NetworkPolicy(policy *netpolicy.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if reason := policy.CheckAdmin(c.ClientIP()); reason != "" {
+			denyNetwork(c, policy, reason)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSensitiveNetwork guards routes that change credentials, which may
+// be limited to a smaller set of networks than the rest of the admin API.
+func RequireSensitiveNetwork(policy *netpolicy.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if reason := policy.CheckSensitive(c.ClientIP()); reason != "" {
+			denyNetwork(c, policy, reason)
+			return
+		}
+		c.Next()
+	}
+}
+
+func denyNetwork(c *gin.Context, policy *netpolicy.Policy, reason string) {
+	denial := netpolicy.Denial{
+		IP:     c.ClientIP(),
+		Method: c.Request.Method,
+		Path:   c.Request.URL.Path,
+		Reason: reason,
+		At:     time.Now(),
+	}
+	count := policy.RecordDenial(denial)
+	log.Printf("network policy: denied %s %s from %s (%s, %d so far)", denial.Method, denial.Path, denial.IP, reason, count)
+
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access from this network is not allowed"})
+}
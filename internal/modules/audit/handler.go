@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ActorFromContext describes the caller authenticated by the auth middleware.
+func ActorFromContext(c *gin.Context) Actor {
+	actor := Actor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	if userID, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		actor.UserID = userID
+	}
+	if keyID, err := uuid.Parse(c.GetString("api_key_id")); err == nil {
+		actor.APIKeyID = &keyID
+	}
+	return actor
+}
+
+// toAuditLogResponse maps an audit entry to the API representation.
+func toAuditLogResponse(entry *AuditLog) gin.H {
+	return gin.H{
+		"id":          entry.ID,
+		"actor_id":    entry.ActorID,
+		"api_key_id":  entry.APIKeyID,
+		"action":      entry.Action,
+		"entity_type": entry.EntityType,
+		"entity_id":   entry.EntityID,
+		"before":      entry.Before,
+		"after":       entry.After,
+		"ip_address":  entry.IPAddress,
+		"user_agent":  entry.UserAgent,
+		"created_at":  entry.CreatedAt,
+	}
+}
+
+// parseTime accepts an RFC 3339 timestamp or a plain date (YYYY-MM-DD).
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("expected RFC 3339 timestamp or YYYY-MM-DD date")
+	}
+	return &t, nil
+}
+
+// GetAuditLogs godoc
+// @Summary      Admin - Get Audit Log
+// @Description  Retrieve a paginated list of changes made through the admin API, newest first
+// @Tags         Admin - Audit
+// @Produce      json
+// @Param        page         query    int     false  "Page number" default(1)
+// @Param        limit        query    int     false  "Items per page" default(10)
+// @Param        actor_id     query    string  false  "User who made the change"
+// @Param        action       query    string  false  "Action (create, update, delete, upload, revoke, enable, disable, regenerate, publish, unpublish, restore)"
+// @Param        entity_type  query    string  false  "Entity type (post, project, skill, profile, ...)"
+// @Param        entity_id    query    string  false  "Entity ID"
+// @Param        from         query    string  false  "Changes at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param        to           query    string  false  "Changes before this time (RFC 3339 or YYYY-MM-DD)"
+// @Security     BearerAuth
+// @Success      200  {object}  pagination.PaginatedResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/audit [get]
+func (h *Handler) GetAuditLogs(c *gin.Context) {
+	filter := Filter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+	}
+
+	if actorParam := c.Query("actor_id"); actorParam != "" {
+		actorID, err := uuid.Parse(actorParam)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid actor_id", "invalid id")
+			return
+		}
+		filter.ActorID = &actorID
+	}
+
+	var err error
+	if filter.From, err = parseTime(c.Query("from")); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid from", err.Error())
+		return
+	}
+	if filter.To, err = parseTime(c.Query("to")); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid to", err.Error())
+		return
+	}
+
+	p := pagination.FromContext(c)
+	result, err := h.service.List(filter, p.Page, p.Limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch audit log", err.Error())
+		return
+	}
+
+	entries := result.Data.([]AuditLog)
+	data := make([]gin.H, 0, len(entries))
+	for i := range entries {
+		data = append(data, toAuditLogResponse(&entries[i]))
+	}
+	result.Data = data
+
+	response.Success(c, http.StatusOK, "Audit log fetched successfully", result)
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDelete     = "delete"
+	ActionUpload     = "upload"
+	ActionRevoke     = "revoke"
+	ActionEnable     = "enable"
+	ActionDisable    = "disable"
+	ActionRegenerate = "regenerate"
+	ActionPublish    = "publish"
+	ActionUnpublish  = "unpublish"
+	ActionRestore    = "restore"
+	ActionMerge      = "merge"
+)
+
+// AuditLog is one change made through the admin API. Before and After hold
+// only the fields that changed; secrets are replaced by a placeholder.
+type AuditLog struct {
+	ID         uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ActorID    *uuid.UUID             `gorm:"type:uuid;index"`
+	APIKeyID   *uuid.UUID             `gorm:"type:uuid"`
+	Action     string                 `gorm:"type:varchar(50);not null;index"`
+	EntityType string                 `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity"`
+	EntityID   string                 `gorm:"type:varchar(255);index:idx_audit_logs_entity"`
+	Before     map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	After      map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	IPAddress  string                 `gorm:"type:varchar(64)"`
+	UserAgent  string                 `gorm:"type:varchar(512)"`
+	CreatedAt  time.Time              `gorm:"index"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// Actor is whoever makes a change: a user, optionally through one of their
+// API keys, and the client they used. The zero Actor stands for the system.
+type Actor struct {
+	UserID    uuid.UUID
+	APIKeyID  *uuid.UUID
+	IP        string
+	UserAgent string
+}
+
+// Filter narrows the audit log listing. Empty fields match everything.
+type Filter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
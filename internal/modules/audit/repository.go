@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(entry *AuditLog) error
+	FindAll(filter Filter, limit, offset int) ([]AuditLog, error)
+	Count(filter Filter) (int64, error)
+	DeleteOlderThan(cutoff time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(entry *AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *repository) filtered(filter Filter) *gorm.DB {
+	query := r.db.Model(&AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func (r *repository) FindAll(filter Filter, limit, offset int) ([]AuditLog, error) {
+	var entries []AuditLog
+	query := r.filtered(filter).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	err := query.Find(&entries).Error
+	return entries, err
+}
+
+func (r *repository) Count(filter Filter) (int64, error) {
+	var count int64
+	err := r.filtered(filter).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&AuditLog{})
+	return result.RowsAffected, result.Error
+}
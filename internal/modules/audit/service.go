@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+	purgeInterval = time.Hour
+)
+
+// Field names containing one of these are never written to the log
+var sensitiveFields = []string{"password", "secret", "token", "hash", "recovery", "code"}
+
+type Service interface {
+	Record(actor Actor, action, entityType, entityID string, before, after interface{})
+	List(filter Filter, page, limit int) (*pagination.PaginatedResponse, error)
+}
+
+type service struct {
+	repo      Repository
+	retention time.Duration
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+func NewService(repo Repository, cfg *config.Config) Service {
+	return &service{
+		repo:      repo,
+		retention: time.Duration(cfg.Audit.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+// Record stores a change of an entity. before is nil for creations and after
+// is nil for deletions; otherwise only the fields that differ are kept. A
+// failure is logged rather than returned, so it never undoes the change.
+func (s *service) Record(actor Actor, action, entityType, entityID string, before, after interface{}) {
+	beforeFields, afterFields := diff(before, after)
+
+	entry := &AuditLog{
+		APIKeyID:   actor.APIKeyID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeFields,
+		After:      afterFields,
+		IPAddress:  actor.IP,
+		UserAgent:  truncate(actor.UserAgent, 512),
+	}
+	if actor.UserID != uuid.Nil {
+		actorID := actor.UserID
+		entry.ActorID = &actorID
+	}
+
+	if err := s.repo.Create(entry); err != nil {
+		log.Printf("Failed to write audit log for %s %s %s: %v", action, entityType, entityID, err)
+	}
+
+	s.purgeExpired()
+}
+
+// purgeExpired removes entries older than AUDIT_RETENTION_DAYS, at most once
+// per purgeInterval.
+func (s *service) purgeExpired() {
+	if s.retention <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if time.Since(s.lastPurged) < purgeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurged = time.Now()
+	s.mu.Unlock()
+
+	if _, err := s.repo.DeleteOlderThan(time.Now().Add(-s.retention)); err != nil {
+		log.Printf("Failed to purge audit log: %v", err)
+	}
+}
+
+func (s *service) List(filter Filter, page, limit int) (*pagination.PaginatedResponse, error) {
+	p := pagination.Pagination{
+		Page:  page,
+		Limit: limit,
+	}
+
+	entries, err := s.repo.FindAll(filter, p.Limit, p.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.Count(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := pagination.NewResponse(entries, total, p)
+	return &res, nil
+}
+
+// diff converts both states to JSON objects and drops the fields they share.
+func diff(before, after interface{}) (map[string]interface{}, map[string]interface{}) {
+	beforeFields := toFields(before)
+	afterFields := toFields(after)
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	redact(beforeFields)
+	redact(afterFields)
+	return beforeFields, afterFields
+}
+
+func toFields(state interface{}) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(state); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	if fields, ok := decoded.(map[string]interface{}); ok {
+		return fields
+	}
+	return map[string]interface{}{"value": decoded}
+}
+
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitive(key) && field != nil {
+				v[key] = redactedValue
+				continue
+			}
+			redact(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redact(item)
+		}
+	}
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, s := range sensitiveFields {
+		if strings.Contains(field, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
@@ -1,10 +1,17 @@
 package auth
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/password"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
 
@@ -23,7 +30,7 @@ type LoginRequest struct {
 
 // Login godoc
 // @Summary      Admin - Login
-// @Description  Authenticates an admin user and returns a JWT token
+// @Description  Authenticates an admin user and returns a short-lived JWT access token and a refresh token. If two-factor authentication is enabled, a challenge token is returned instead.
 // @Tags         Admin - Auth
 // @Accept       json
 // @Produce      json
@@ -31,6 +38,7 @@ type LoginRequest struct {
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
 // @Router       /admin/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -39,27 +47,441 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	token, user, err := h.service.Login(req.Email, req.Password)
+	result, err := h.service.Login(req.Email, req.Password, clientInfo(c))
 	if err != nil {
-		response.Error(c, http.StatusUnauthorized, "Login failed", err.Error())
+		writeLoginError(c, err)
+		return
+	}
+
+	writeLoginResponse(c, result)
+}
+
+// writeLoginResponse answers either with the issued tokens or, when a second
+// factor is required, with the challenge to complete at /admin/login/2fa.
+func writeLoginResponse(c *gin.Context, result *LoginResult) {
+	if result.ChallengeToken != "" {
+		response.Success(c, http.StatusOK, "Two-factor authentication required", gin.H{
+			"two_factor_required":      true,
+			"challenge_token":          result.ChallengeToken,
+			"expires_in":               result.ChallengeTTL,
+			"password_change_required": result.PasswordChangeRequired,
+		})
 		return
 	}
 
+	user := result.User
 	fullname := ""
 	if user.Profile != nil {
 		fullname = user.Profile.FullName
 	}
 
 	response.Success(c, http.StatusOK, "Login successful", gin.H{
-		"token": token,
+		"token":                    result.Tokens.AccessToken,
+		"refresh_token":            result.Tokens.RefreshToken,
+		"expires_in":               result.Tokens.ExpiresIn,
+		"password_change_required": result.PasswordChangeRequired,
 		"user": gin.H{
 			"id":       user.ID,
 			"email":    user.Email,
 			"fullname": fullname,
+			"role":     user.Role,
 		},
 	})
 }
 
+// writeLoginError sets Retry-After when attempts are being throttled and
+// answers 429 if the attempt was refused without checking the credentials.
+func writeLoginError(c *gin.Context, err error) {
+	var throttleErr *ThrottleError
+	if errors.As(err, &throttleErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttleErr.RetryAfter.Seconds()))))
+		if throttleErr.Err == nil {
+			response.Error(c, http.StatusTooManyRequests, "Too many login attempts", err.Error())
+			return
+		}
+	}
+	response.Error(c, http.StatusUnauthorized, "Login failed", err.Error())
+}
+
+// writeRateLimitError answers 429 with Retry-After when too many emails were
+// requested, and reports whether it did.
+func writeRateLimitError(c *gin.Context, err error) bool {
+	var limitErr *RateLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+	response.Error(c, http.StatusTooManyRequests, "Too many requests", err.Error())
+	return true
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// LoginTwoFactor godoc
+// @Summary      Admin - Complete Two-Factor Login
+// @Description  Completes a login challenge with a TOTP code or an unused recovery code and returns the tokens
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body TwoFactorLoginRequest true "Challenge and Code"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Router       /admin/login/2fa [post]
+func (h *Handler) LoginTwoFactor(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.service.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		writeLoginError(c, err)
+		return
+	}
+
+	writeLoginResponse(c, result)
+}
+
+// OIDCAuthorize godoc
+// @Summary      Admin - Start OIDC Login
+// @Description  Starts an authorization-code + PKCE login with the configured OpenID Connect provider. Redirect the browser to authorization_url; the provider sends it back to OIDC_REDIRECT_URL with code and state.
+// @Tags         Admin - Auth
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Failure      502  {object}  map[string]string
+// @Router       /admin/oidc/authorize [get]
+func (h *Handler) OIDCAuthorize(c *gin.Context) {
+	authorization, err := h.service.StartOIDCLogin()
+	if err != nil {
+		if errors.Is(err, ErrOIDCDisabled) {
+			response.Error(c, http.StatusNotFound, "OIDC login unavailable", err.Error())
+			return
+		}
+		response.Error(c, http.StatusBadGateway, "Failed to start OIDC login", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Redirect to the identity provider", gin.H{
+		"authorization_url": authorization.URL,
+		"state":             authorization.State,
+		"expires_in":        authorization.ExpiresIn,
+	})
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// OIDCCallback godoc
+// @Summary      Admin - Complete OIDC Login
+// @Description  Exchanges the code and state received from the identity provider for the normal access and refresh tokens. The subject or verified email must be allowlisted and match an existing user.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body OIDCCallbackRequest true "Code and State"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/oidc/callback [post]
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.service.CompleteOIDCLogin(req.Code, req.State, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, ErrOIDCDisabled) {
+			response.Error(c, http.StatusNotFound, "OIDC login unavailable", err.Error())
+			return
+		}
+		response.Error(c, http.StatusUnauthorized, "Login failed", err.Error())
+		return
+	}
+
+	writeLoginResponse(c, result)
+}
+
+type MagicLinkRequestBody struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// RequestMagicLink godoc
+// @Summary      Admin - Request Magic Link
+// @Description  Emails a single-use sign-in link if the address belongs to an account and returns a nonce. Keep the nonce in the requesting browser; the link only works together with it. The response is the same whether or not the account exists. Each email address and client IP may request a limited number of links per window.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body MagicLinkRequestBody true "Email"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/login/magic-link [post]
+func (h *Handler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.service.RequestMagicLink(req.Email, clientInfo(c))
+	if err != nil {
+		if writeRateLimitError(c, err) {
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to request magic link", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "If the email belongs to an account, a sign-in link has been sent", gin.H{
+		"nonce":      result.Nonce,
+		"expires_in": result.ExpiresIn,
+	})
+}
+
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+	Nonce string `json:"nonce" binding:"required"`
+}
+
+// VerifyMagicLink godoc
+// @Summary      Admin - Complete Magic Link Login
+// @Description  Exchanges the token from the magic link email and the nonce returned when it was requested for the same tokens as a password login. If two-factor authentication is enabled, a challenge token is returned instead.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body MagicLinkVerifyRequest true "Token and Nonce"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/login/magic-link/verify [post]
+func (h *Handler) VerifyMagicLink(c *gin.Context) {
+	var req MagicLinkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.service.CompleteMagicLinkLogin(req.Token, req.Nonce, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, ErrInvalidMagicLink) || errors.Is(err, ErrAccountDisabled) {
+			response.Error(c, http.StatusUnauthorized, "Login failed", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Login failed", err.Error())
+		return
+	}
+
+	writeLoginResponse(c, result)
+}
+
+// PasskeyLoginBegin godoc
+// @Summary      Admin - Start Passkey Login
+// @Description  Returns WebAuthn assertion options for navigator.credentials.get() and a ceremony token. Any discoverable passkey of this site can be used; no credentials are listed, so the response does not reveal which accounts exist.
+// @Tags         Admin - Auth
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/login/passkey/begin [post]
+func (h *Handler) PasskeyLoginBegin(c *gin.Context) {
+	start, err := h.service.BeginPasskeyLogin()
+	if err != nil {
+		writePasskeyError(c, "Failed to start passkey login", err)
+		return
+	}
+	response.Success(c, http.StatusOK, "Passkey login started", toPasskeyCeremonyResponse(start))
+}
+
+type PasskeyLoginFinishRequest struct {
+	CeremonyToken string          `json:"ceremony_token" binding:"required"`
+	Credential    json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// PasskeyLoginFinish godoc
+// @Summary      Admin - Complete Passkey Login
+// @Description  Verifies the PublicKeyCredential returned by navigator.credentials.get() and returns the same tokens as a password login
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body PasskeyLoginFinishRequest true "Ceremony Token and Credential"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/login/passkey/finish [post]
+func (h *Handler) PasskeyLoginFinish(c *gin.Context) {
+	var req PasskeyLoginFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := h.service.FinishPasskeyLogin(req.CeremonyToken, req.Credential, clientInfo(c))
+	if err != nil {
+		writePasskeyError(c, "Login failed", err)
+		return
+	}
+
+	writeLoginResponse(c, result)
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh godoc
+// @Summary      Admin - Refresh Token
+// @Description  Exchanges a refresh token for a new access token and a new refresh token. The presented refresh token is revoked; reusing it revokes the whole login.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "Refresh Token"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/refresh [post]
+func (h *Handler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Refresh failed", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token refreshed successfully", gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout godoc
+// @Summary      Admin - Logout
+// @Description  Revokes the refresh token and every token rotated from the same login
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "Refresh Token"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/logout [post]
+func (h *Handler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			response.Error(c, http.StatusUnauthorized, "Logout failed", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Logout failed", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPassword godoc
+// @Summary      Admin - Forgot Password
+// @Description  Emails a single-use password reset link if the address belongs to an account. The response is the same either way. Requests are limited per email and per client IP; over the limit the answer is 429 with Retry-After.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ForgotPasswordRequest true "Email"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/forgot-password [post]
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(req.Email, clientInfo(c)); err != nil {
+		if writeRateLimitError(c, err) {
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to request password reset", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "If the email belongs to an account, a reset link has been sent", nil)
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ResetPassword godoc
+// @Summary      Admin - Reset Password
+// @Description  Sets a new password using the token from the reset email and signs out every session
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResetPasswordRequest true "Token and New Password"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/reset-password [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.service.ResetPassword(req.Token, req.Password); err != nil {
+		if errors.Is(err, ErrInvalidResetToken) {
+			response.Error(c, http.StatusBadRequest, "Failed to reset password", err.Error())
+			return
+		}
+		writePasswordError(c, "Failed to reset password", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// writePasswordError answers 422 with every broken rule when a new password
+// is rejected by the password policy.
+func writePasswordError(c *gin.Context, message string, err error) {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		response.Error(c, http.StatusUnprocessableEntity, "Password does not meet the password policy", err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, message, err.Error())
+}
+
 type UpdateEmailRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
@@ -91,7 +513,7 @@ func (h *Handler) UpdateEmail(c *gin.Context) {
 	}
 	userID, _ := uuid.Parse(userIDVal.(string))
 
-	if err := h.service.UpdateEmail(userID, req.Email); err != nil {
+	if err := h.service.UpdateEmail(audit.ActorFromContext(c), userID, req.Email); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update email", err.Error())
 		return
 	}
@@ -100,12 +522,12 @@ func (h *Handler) UpdateEmail(c *gin.Context) {
 }
 
 type UpdatePasswordRequest struct {
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 // UpdatePassword godoc
 // @Summary      Admin - Update Password
-// @Description  Update the authenticated admin's password
+// @Description  Update the authenticated admin's password. The password must satisfy the password policy and differ from recent passwords. Every other session of the user is logged out.
 // @Tags         Admin - Auth
 // @Accept       json
 // @Produce      json
@@ -114,6 +536,7 @@ type UpdatePasswordRequest struct {
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/update-password [put]
 func (h *Handler) UpdatePassword(c *gin.Context) {
@@ -129,11 +552,758 @@ func (h *Handler) UpdatePassword(c *gin.Context) {
 		return
 	}
 	userID, _ := uuid.Parse(userIDVal.(string))
+	sessionID, _ := currentSessionID(c)
 
-	if err := h.service.UpdatePassword(userID, req.Password); err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to update password", err.Error())
+	if err := h.service.UpdatePassword(audit.ActorFromContext(c), userID, sessionID, req.Password); err != nil {
+		writePasswordError(c, "Failed to update password", err)
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Password updated successfully", nil)
 }
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// SetupTOTP godoc
+// @Summary      Admin - Start Two-Factor Setup
+// @Description  Generates a new TOTP secret and provisioning URI for an authenticator app. 2FA is not active until confirmed.
+// @Tags         Admin - Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/2fa/setup [post]
+func (h *Handler) SetupTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	setup, err := h.service.SetupTOTP(userID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to start two-factor setup", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor setup started", gin.H{
+		"secret":           setup.Secret,
+		"provisioning_uri": setup.ProvisioningURI,
+	})
+}
+
+// EnableTOTP godoc
+// @Summary      Admin - Enable Two-Factor Authentication
+// @Description  Confirms the TOTP secret with a code, enables 2FA and returns one-time recovery codes
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body TOTPCodeRequest true "TOTP Code"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/2fa/enable [post]
+func (h *Handler) EnableTOTP(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	codes, err := h.service.EnableTOTP(audit.ActorFromContext(c), userID, req.Code)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to enable two-factor authentication", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication enabled", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTOTP godoc
+// @Summary      Admin - Disable Two-Factor Authentication
+// @Description  Disables 2FA after verifying a TOTP or recovery code
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body TOTPCodeRequest true "TOTP or Recovery Code"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/2fa/disable [post]
+func (h *Handler) DisableTOTP(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	if err := h.service.DisableTOTP(audit.ActorFromContext(c), userID, req.Code); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to disable two-factor authentication", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Admin - Regenerate Recovery Codes
+// @Description  Replaces all recovery codes after verifying a TOTP code. Previous codes stop working.
+// @Tags         Admin - Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body TOTPCodeRequest true "TOTP Code"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(audit.ActorFromContext(c), userID, req.Code)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to regenerate recovery codes", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Recovery codes regenerated", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// GetLoginLockouts godoc
+// @Summary      Admin - Get Login Lockouts
+// @Description  Lists failed-login counters per email and client IP, including active back-offs and lockouts
+// @Tags         Admin - Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   LoginThrottle
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/login-lockouts [get]
+func (h *Handler) GetLoginLockouts(c *gin.Context) {
+	throttles, err := h.service.ListLoginThrottles()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch login lockouts", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Login lockouts fetched successfully", throttles)
+}
+
+// ClearLoginLockout godoc
+// @Summary      Admin - Clear Login Lockout
+// @Description  Resets the failed-login counter and lifts any lockout for one email or client IP
+// @Tags         Admin - Auth
+// @Produce      json
+// @Param        id   path     string  true  "Lockout ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/login-lockouts/{id} [delete]
+func (h *Handler) ClearLoginLockout(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	if err := h.service.ClearLoginThrottle(audit.ActorFromContext(c), id); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to clear login lockout", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Login lockout cleared successfully", nil)
+}
+
+type InviteUserRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+// toUserResponse exposes the fields of a user that are safe to return.
+func toUserResponse(user *User) gin.H {
+	fullname := ""
+	if user.Profile != nil {
+		fullname = user.Profile.FullName
+	}
+	return gin.H{
+		"id":           user.ID,
+		"email":        user.Email,
+		"fullname":     fullname,
+		"role":         user.Role,
+		"totp_enabled": user.TOTPEnabled,
+		"disabled_at":  user.DisabledAt,
+		"created_at":   user.CreatedAt,
+	}
+}
+
+// GetUsers godoc
+// @Summary      Admin - Get All Users
+// @Description  Retrieve all admin accounts with their roles
+// @Tags         Admin - Users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/users [get]
+func (h *Handler) GetUsers(c *gin.Context) {
+	users, err := h.service.ListUsers()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch users", err.Error())
+		return
+	}
+
+	result := make([]gin.H, 0, len(users))
+	for i := range users {
+		result = append(result, toUserResponse(&users[i]))
+	}
+	response.Success(c, http.StatusOK, "Users fetched successfully", result)
+}
+
+// InviteUser godoc
+// @Summary      Admin - Invite User
+// @Description  Create an account with a role (owner, editor or viewer). The temporary password is returned only once.
+// @Tags         Admin - Users
+// @Accept       json
+// @Produce      json
+// @Param        request body InviteUserRequest true "User Data"
+// @Security     BearerAuth
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Router       /admin/users [post]
+func (h *Handler) InviteUser(c *gin.Context) {
+	var req InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	user, tempPassword, err := h.service.InviteUser(audit.ActorFromContext(c), req.Email, req.Role)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to invite user", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "User invited successfully", gin.H{
+		"user":               toUserResponse(user),
+		"temporary_password": tempPassword,
+	})
+}
+
+// UpdateUserRole godoc
+// @Summary      Admin - Update User Role
+// @Description  Change another user's role. The last owner cannot be demoted.
+// @Tags         Admin - Users
+// @Accept       json
+// @Produce      json
+// @Param        id   path     string  true  "User ID"
+// @Param        request body UpdateUserRoleRequest true "Role"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/users/{id}/role [put]
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	actor := audit.ActorFromContext(c)
+	if actor.UserID == uuid.Nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	user, err := h.service.UpdateUserRole(actor, id, req.Role)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update user role", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "User role updated successfully", toUserResponse(user))
+}
+
+// DeleteUser godoc
+// @Summary      Admin - Delete User
+// @Description  Delete another user's account. The last owner cannot be deleted.
+// @Tags         Admin - Users
+// @Produce      json
+// @Param        id   path     string  true  "User ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	actor := audit.ActorFromContext(c)
+	if actor.UserID == uuid.Nil {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	if err := h.service.DeleteUser(actor, id); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to delete user", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "User deleted successfully", nil)
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339, optional
+}
+
+func toAPIKeyResponse(key *APIKey) gin.H {
+	return gin.H{
+		"id":           key.ID,
+		"user_id":      key.UserID,
+		"name":         key.Name,
+		"prefix":       key.Prefix,
+		"scopes":       key.Scopes,
+		"expires_at":   key.ExpiresAt,
+		"last_used_at": key.LastUsedAt,
+		"revoked_at":   key.RevokedAt,
+		"created_at":   key.CreatedAt,
+	}
+}
+
+// GetAPIKeys godoc
+// @Summary      Admin - Get All API Keys
+// @Description  Retrieve all API keys with scopes, expiry, last use and revocation time. Secrets are never returned.
+// @Tags         Admin - API Keys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/api-keys [get]
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.service.ListAPIKeys()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch API keys", err.Error())
+		return
+	}
+
+	result := make([]gin.H, 0, len(keys))
+	for i := range keys {
+		result = append(result, toAPIKeyResponse(&keys[i]))
+	}
+	response.Success(c, http.StatusOK, "API keys fetched successfully", result)
+}
+
+// CreateAPIKey godoc
+// @Summary      Admin - Create API Key
+// @Description  Create a scoped API key for scripts and CI. Scopes use permission names such as posts:write, images:upload or messages:read. The key is returned only once.
+// @Tags         Admin - API Keys
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateAPIKeyRequest true "API Key Data"
+// @Security     BearerAuth
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/api-keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	key, plainKey, err := h.service.CreateAPIKey(audit.ActorFromContext(c), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create API key", err.Error())
+		return
+	}
+
+	result := toAPIKeyResponse(key)
+	result["key"] = plainKey
+	response.Success(c, http.StatusCreated, "API key created successfully", result)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Admin - Revoke API Key
+// @Description  Revoke an API key immediately
+// @Tags         Admin - API Keys
+// @Produce      json
+// @Param        id   path     string  true  "API Key ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(audit.ActorFromContext(c), id); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to revoke API key", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "API key revoked successfully", nil)
+}
+
+func toSessionResponse(session *Session, currentID uuid.UUID) gin.H {
+	return gin.H{
+		"id":           session.ID,
+		"user_agent":   session.UserAgent,
+		"ip_address":   session.IPAddress,
+		"created_at":   session.CreatedAt,
+		"last_seen_at": session.LastSeenAt,
+		"expires_at":   session.ExpiresAt,
+		"current":      session.ID == currentID,
+	}
+}
+
+// GetSessions godoc
+// @Summary      Admin - Get Sessions
+// @Description  List the authenticated user's active sessions (one per login). The session making the request is flagged as current.
+// @Tags         Admin - Sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/sessions [get]
+func (h *Handler) GetSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+	currentID, _ := currentSessionID(c)
+
+	sessions, err := h.service.ListSessions(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch sessions", err.Error())
+		return
+	}
+
+	result := make([]gin.H, 0, len(sessions))
+	for i := range sessions {
+		result = append(result, toSessionResponse(&sessions[i], currentID))
+	}
+	response.Success(c, http.StatusOK, "Sessions fetched successfully", result)
+}
+
+// RevokeSession godoc
+// @Summary      Admin - Revoke Session
+// @Description  Log out one of the authenticated user's sessions. Its access and refresh tokens stop working immediately.
+// @Tags         Admin - Sessions
+// @Produce      json
+// @Param        id   path     string  true  "Session ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/sessions/{id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	if err := h.service.RevokeSession(audit.ActorFromContext(c), userID, id); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			response.Error(c, http.StatusNotFound, "Failed to revoke session", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to revoke session", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeAllSessions godoc
+// @Summary      Admin - Log Out Everywhere
+// @Description  Revoke every session of the authenticated user, including the current one.
+// @Tags         Admin - Sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/sessions [delete]
+func (h *Handler) RevokeAllSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	if err := h.service.RevokeAllSessions(audit.ActorFromContext(c), userID); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to revoke sessions", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "All sessions revoked successfully", nil)
+}
+
+func toPasskeyResponse(passkey *Passkey) gin.H {
+	return gin.H{
+		"id":           passkey.ID,
+		"name":         passkey.Name,
+		"transports":   passkey.Transports,
+		"backed_up":    passkey.BackupState,
+		"last_used_at": passkey.LastUsedAt,
+		"created_at":   passkey.CreatedAt,
+	}
+}
+
+func toPasskeyCeremonyResponse(start *PasskeyCeremonyStart) gin.H {
+	return gin.H{
+		"ceremony_token": start.Token,
+		"options":        start.Options,
+		"expires_in":     start.ExpiresIn,
+	}
+}
+
+// writePasskeyError maps passkey failures to 404 when passkeys are not
+// configured, 401 for failed verification and 500 otherwise.
+func writePasskeyError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, ErrPasskeysUnavailable), errors.Is(err, ErrPasskeyNotFound):
+		response.Error(c, http.StatusNotFound, message, err.Error())
+	case errors.Is(err, ErrInvalidCeremony), errors.Is(err, ErrInvalidPasskey), errors.Is(err, ErrAccountDisabled):
+		response.Error(c, http.StatusUnauthorized, message, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// GetPasskeys godoc
+// @Summary      Admin - Get Passkeys
+// @Description  List the passkeys registered for the authenticated user
+// @Tags         Admin - Passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/passkeys [get]
+func (h *Handler) GetPasskeys(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	passkeys, err := h.service.ListPasskeys(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch passkeys", err.Error())
+		return
+	}
+
+	result := make([]gin.H, 0, len(passkeys))
+	for i := range passkeys {
+		result = append(result, toPasskeyResponse(&passkeys[i]))
+	}
+	response.Success(c, http.StatusOK, "Passkeys fetched successfully", result)
+}
+
+// BeginPasskeyRegistration godoc
+// @Summary      Admin - Start Passkey Registration
+// @Description  Returns WebAuthn creation options for navigator.credentials.create() and a ceremony token
+// @Tags         Admin - Passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/passkeys/register/begin [post]
+func (h *Handler) BeginPasskeyRegistration(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	start, err := h.service.BeginPasskeyRegistration(userID)
+	if err != nil {
+		writePasskeyError(c, "Failed to start passkey registration", err)
+		return
+	}
+	response.Success(c, http.StatusOK, "Passkey registration started", toPasskeyCeremonyResponse(start))
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	CeremonyToken string          `json:"ceremony_token" binding:"required"`
+	Name          string          `json:"name" binding:"max=100"`
+	Credential    json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// FinishPasskeyRegistration godoc
+// @Summary      Admin - Complete Passkey Registration
+// @Description  Verifies the PublicKeyCredential returned by navigator.credentials.create() and stores the passkey
+// @Tags         Admin - Passkeys
+// @Accept       json
+// @Produce      json
+// @Param        request body FinishPasskeyRegistrationRequest true "Ceremony Token, Name and Credential"
+// @Security     BearerAuth
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Router       /admin/passkeys/register/finish [post]
+func (h *Handler) FinishPasskeyRegistration(c *gin.Context) {
+	var req FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	passkey, err := h.service.FinishPasskeyRegistration(audit.ActorFromContext(c), userID, req.CeremonyToken, req.Name, req.Credential)
+	if err != nil {
+		writePasskeyError(c, "Failed to register passkey", err)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Passkey registered successfully", toPasskeyResponse(passkey))
+}
+
+// DeletePasskey godoc
+// @Summary      Admin - Delete Passkey
+// @Description  Remove one of the authenticated user's passkeys
+// @Tags         Admin - Passkeys
+// @Produce      json
+// @Param        id   path     string  true  "Passkey ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/passkeys/{id} [delete]
+func (h *Handler) DeletePasskey(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	if err := h.service.DeletePasskey(audit.ActorFromContext(c), userID, id); err != nil {
+		writePasskeyError(c, "Failed to delete passkey", err)
+		return
+	}
+	response.Success(c, http.StatusOK, "Passkey deleted successfully", nil)
+}
+
+// JWKS serves /.well-known/jwks.json: the public keys that verify admin access
+// tokens, selected by the token's kid header. Empty when tokens are signed HS256.
+func (h *Handler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.service.JWKS())
+}
+
+// clientInfo describes the calling device for session tracking and throttling.
+func clientInfo(c *gin.Context) ClientInfo {
+	return ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// currentSessionID reads the session ID set by the auth middleware for user tokens.
+func currentSessionID(c *gin.Context) (uuid.UUID, bool) {
+	sessionIDVal, exists := c.Get("session_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+	sessionIDStr, ok := sessionIDVal.(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return sessionID, true
+}
+
+// currentUserID reads the authenticated user's ID set by the auth middleware.
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+	userIDStr, ok := userIDVal.(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
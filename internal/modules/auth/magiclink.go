@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/mailer"
+)
+
+var ErrInvalidMagicLink = errors.New("invalid or expired magic link")
+
+// MagicLinkRequest is returned when a magic link is requested. The nonce must
+// be kept by the requesting browser and sent back with the token from the
+// email; a link opened anywhere else is rejected.
+type MagicLinkRequest struct {
+	Nonce     string
+	ExpiresIn int64 // seconds
+}
+
+// RequestMagicLink emails a single-use login link bound to a fresh nonce. The
+// nonce is returned for unknown and disabled addresses too, and the link is
+// created and sent in the background, so neither the response nor its timing
+// reveals whether an account exists.
+func (s *service) RequestMagicLink(email string, client ClientInfo) (*MagicLinkRequest, error) {
+	if err := s.limitEmailRequests("magic-link", email, client); err != nil {
+		return nil, err
+	}
+
+	nonce, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Minute * time.Duration(s.cfg.Auth.MagicLinkTTL)
+	go s.sendMagicLink(email, nonce, ttl, client)
+
+	return &MagicLinkRequest{Nonce: nonce, ExpiresIn: int64(ttl.Seconds())}, nil
+}
+
+// sendMagicLink stores and emails a link for the account of the email, if
+// there is an enabled one. Failures are only logged, as the request has
+// already been answered.
+func (s *service) sendMagicLink(email, nonce string, ttl time.Duration, client ClientInfo) {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		log.Printf("failed to create magic link: %v", err)
+		return
+	}
+	if user == nil || user.DisabledAt != nil {
+		return
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	token, err := s.signMagicLink(expiresAt, hashToken(nonce))
+	if err != nil {
+		log.Printf("failed to create magic link: %v", err)
+		return
+	}
+
+	magicLink := &MagicLinkToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		NonceHash: hashToken(nonce),
+		IPAddress: client.IP,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.CreateMagicLinkToken(magicLink); err != nil {
+		log.Printf("failed to create magic link: %v", err)
+		return
+	}
+
+	link := s.cfg.Auth.MagicLinkURL + "?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("A sign-in link was requested for your account.\n\n"+
+			"Open the link below in the same browser to sign in. It expires in %d minutes and can be used once.\n\n%s\n\n"+
+			"If you did not request this, you can ignore this email.\n", s.cfg.Auth.MagicLinkTTL, link),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("failed to send magic link email: %v", err)
+	}
+}
+
+// CompleteMagicLinkLogin exchanges a magic link token and the nonce of the
+// browser that requested it for tokens, or for a login challenge when the
+// user has two-factor authentication enabled.
+func (s *service) CompleteMagicLinkLogin(token, nonce string, client ClientInfo) (*LoginResult, error) {
+	// Check the signature and expiry first so that a link opened in the wrong
+	// browser is rejected without using it up
+	if !s.verifyMagicLink(token, hashToken(nonce), time.Now()) {
+		return nil, ErrInvalidMagicLink
+	}
+
+	magicLink, err := s.repo.ConsumeMagicLinkToken(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if magicLink == nil || time.Now().After(magicLink.ExpiresAt) ||
+		!hmac.Equal([]byte(magicLink.NonceHash), []byte(hashToken(nonce))) {
+		return nil, ErrInvalidMagicLink
+	}
+
+	user, err := s.repo.FindByID(magicLink.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidMagicLink
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrAccountDisabled
+	}
+
+	// The link only proves access to the mailbox, so it replaces the password
+	// but not the second factor
+	if user.TOTPEnabled {
+		return s.createLoginChallenge(user)
+	}
+
+	tokens, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+func (s *service) magicLinkSecret() []byte {
+	if s.cfg.Auth.MagicLinkSecret != "" {
+		return []byte(s.cfg.Auth.MagicLinkSecret)
+	}
+	return []byte(s.cfg.JWT.Secret)
+}
+
+// signMagicLink builds a token of the form "<expiry>.<random>.<signature>",
+// where the HMAC covers the expiry, the random part and the nonce hash.
+func (s *service) signMagicLink(expiresAt time.Time, nonceHash string) (string, error) {
+	random, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	payload := strconv.FormatInt(expiresAt.Unix(), 10) + "." + random
+	return payload + "." + s.magicLinkSignature(payload, nonceHash), nil
+}
+
+func (s *service) verifyMagicLink(token, nonceHash string, now time.Time) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.magicLinkSignature(payload, nonceHash))) {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return false
+	}
+	return now.Before(time.Unix(expires, 0))
+}
+
+func (s *service) magicLinkSignature(payload, nonceHash string) string {
+	mac := hmac.New(sha256.New, s.magicLinkSecret())
+	mac.Write([]byte(payload + "." + nonceHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
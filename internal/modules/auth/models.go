@@ -3,6 +3,7 @@ package auth
 import (
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
 )
@@ -10,7 +11,13 @@ import (
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	Email        string    `gorm:"type:varchar(255);unique;not null"`
-	PasswordHash string            `gorm:"type:varchar(255);not null"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"`
+	TOTPSecret   string    `gorm:"column:totp_secret;type:varchar(64)"`
+	TOTPEnabled  bool      `gorm:"column:totp_enabled;default:false"`
+	TOTPLastStep int64     `gorm:"column:totp_last_step;not null;default:0"`          // time step of the last accepted code
+	OIDCSubject  *string   `gorm:"column:oidc_subject;type:varchar(255);uniqueIndex"` // bound on the first OIDC login
+	Role         string    `gorm:"type:varchar(20);not null;default:'viewer'"`
+	DisabledAt   *time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	Profile      *profiles.Profile `gorm:"foreignKey:UserID"`
@@ -19,3 +26,287 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// auditState is the part of a user recorded in the audit log.
+func (u *User) auditState() map[string]interface{} {
+	return map[string]interface{}{
+		"Email":       u.Email,
+		"Role":        u.Role,
+		"TOTPEnabled": u.TOTPEnabled,
+		"Disabled":    u.DisabledAt != nil,
+	}
+}
+
+// Roles
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Permissions checked by middleware.RequirePermission
+const (
+	PermProfileRead      = "profile:read"
+	PermProfileWrite     = "profile:write"
+	PermPostsRead        = "posts:read"
+	PermPostsWrite       = "posts:write"
+	PermProjectsRead     = "projects:read"
+	PermProjectsWrite    = "projects:write"
+	PermSkillsRead       = "skills:read"
+	PermSkillsWrite      = "skills:write"
+	PermExperiencesRead  = "experiences:read"
+	PermExperiencesWrite = "experiences:write"
+	PermImagesUpload     = "images:upload"
+	PermImagesDelete     = "images:delete"
+	PermMessagesRead     = "messages:read"
+	PermUsersManage      = "users:manage"
+	PermSecurityManage   = "security:manage"
+	PermAPIKeysManage    = "api-keys:manage"
+	PermAuditRead        = "audit:read"
+)
+
+var rolePermissions = map[string][]string{
+	RoleOwner: {
+		PermProfileRead, PermProfileWrite,
+		PermPostsRead, PermPostsWrite,
+		PermProjectsRead, PermProjectsWrite,
+		PermSkillsRead, PermSkillsWrite,
+		PermExperiencesRead, PermExperiencesWrite,
+		PermImagesUpload, PermImagesDelete,
+		PermMessagesRead,
+		PermUsersManage,
+		PermSecurityManage,
+		PermAPIKeysManage,
+		PermAuditRead,
+	},
+	RoleEditor: {
+		PermPostsRead, PermPostsWrite,
+		PermProjectsRead, PermProjectsWrite,
+		PermImagesUpload, PermImagesDelete,
+	},
+	RoleViewer: {
+		PermProfileRead,
+		PermPostsRead,
+		PermProjectsRead,
+		PermSkillsRead,
+		PermExperiencesRead,
+	},
+}
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsValidPermission reports whether permission exists. Owners hold every permission.
+func IsValidPermission(permission string) bool {
+	for _, p := range rolePermissions[RoleOwner] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsForRole returns the permissions granted to role, or nil for an unknown role.
+func PermissionsForRole(role string) []string {
+	return rolePermissions[role]
+}
+
+// PasswordHistory keeps previous password hashes of a user so that recent
+// passwords cannot be reused.
+type PasswordHistory struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+// Session represents one login on one device. Its ID is carried in the "sid"
+// claim of every access token and is the FamilyID of its refresh tokens, so
+// revoking the session also ends the refresh chain.
+type Session struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserAgent  string    `gorm:"type:varchar(512)"`
+	IPAddress  string    `gorm:"type:varchar(64)"`
+	LastSeenAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// RefreshToken is a long-lived, single-use token exchanged for a new access token.
+// Only the SHA-256 hash of the token is stored. Tokens issued from the same login
+// share a FamilyID (the Session ID) so the whole chain can be revoked when reuse
+// is detected.
+type RefreshToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	FamilyID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash  string    `gorm:"type:varchar(64);unique;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+	ReplacedBy *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RecoveryCode is a single-use fallback for the TOTP second factor.
+type RecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// LoginChallenge is issued after a correct password when the user has 2FA
+// enabled. It must be completed with a code before any token is issued.
+type LoginChallenge struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);unique;not null"`
+	Attempts  int       `gorm:"default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+// LoginThrottle tracks consecutive failed logins for one key, either
+// "email:<address>" or "ip:<address>". BlockedUntil is set by the exponential
+// back-off; Locked marks that the failure threshold was reached.
+type LoginThrottle struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Key           string    `gorm:"type:varchar(320);unique;not null"`
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	BlockedUntil  *time.Time
+	Locked        bool `gorm:"default:false"`
+	CreatedAt     time.Time
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// PasswordResetToken is a single-use token emailed by the forgot-password flow.
+type PasswordResetToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);unique;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// MagicLinkToken is an emailed login link. It is bound to the browser that
+// requested it by the hash of a nonce only that browser received.
+type MagicLinkToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);unique;not null"`
+	NonceHash string    `gorm:"type:varchar(64);not null"`
+	IPAddress string    `gorm:"type:varchar(64)"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (MagicLinkToken) TableName() string {
+	return "magic_link_tokens"
+}
+
+// OIDCLoginState keeps the state, nonce and PKCE verifier of an authorization
+// request until the provider redirects back. Only the hash of the state is stored.
+type OIDCLoginState struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	StateHash    string    `gorm:"type:varchar(64);unique;not null"`
+	Nonce        string    `gorm:"type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// Passkey is a WebAuthn credential that lets a user log in without a password.
+type Passkey struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name            string    `gorm:"type:varchar(100);not null"`
+	CredentialID    []byte    `gorm:"type:bytea;unique;not null"`
+	PublicKey       []byte    `gorm:"type:bytea;not null"`
+	AttestationType string    `gorm:"type:varchar(32)"`
+	Transports      []string  `gorm:"type:jsonb;serializer:json"`
+	AAGUID          []byte    `gorm:"column:aaguid;type:bytea"`
+	SignCount       int64     `gorm:"not null;default:0"`
+	BackupEligible  bool      `gorm:"default:false"`
+	BackupState     bool      `gorm:"default:false"`
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+func (Passkey) TableName() string {
+	return "passkeys"
+}
+
+// PasskeyCeremony stores the WebAuthn session data between the begin and
+// finish steps of a registration or login. UserID is set for registrations;
+// logins are usernameless, so the passkey names the user.
+type PasskeyCeremony struct {
+	ID        uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TokenHash string               `gorm:"type:varchar(64);unique;not null"`
+	Kind      string               `gorm:"type:varchar(20);not null"`
+	UserID    *uuid.UUID           `gorm:"type:uuid"`
+	Session   webauthn.SessionData `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt time.Time            `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (PasskeyCeremony) TableName() string {
+	return "passkey_ceremonies"
+}
+
+// APIKey authenticates scripts and CI jobs through the X-API-Key header.
+// Only the SHA-256 hash is stored; Prefix is kept to help recognise a key.
+type APIKey struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Prefix     string    `gorm:"type:varchar(16);not null"`
+	KeyHash    string    `gorm:"type:varchar(64);unique;not null"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcStateTTL       = 10 * time.Minute
+	oidcRequestTimeout = 10 * time.Second
+)
+
+var (
+	ErrOIDCDisabled     = errors.New("OIDC login is not configured")
+	ErrOIDCInvalidState = errors.New("invalid or expired OIDC state")
+	ErrOIDCNotAllowed   = errors.New("this identity is not allowed to sign in")
+)
+
+// OIDCAuthorization is where the browser must be sent to sign in with the
+// identity provider.
+type OIDCAuthorization struct {
+	URL       string
+	State     string
+	ExpiresIn int64 // seconds
+}
+
+// oidcClient discovers the provider on first use, so the server starts even
+// when the provider is unreachable.
+type oidcClient struct {
+	cfg      config.OIDCConfig
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func newOIDCClient(cfg config.OIDCConfig) *oidcClient {
+	return &oidcClient{cfg: cfg}
+}
+
+func (o *oidcClient) enabled() bool {
+	return o.cfg.Issuer != "" && o.cfg.ClientID != ""
+}
+
+func (o *oidcClient) setup(ctx context.Context) (*oidc.Provider, *oauth2.Config, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.provider == nil {
+		provider, err := oidc.NewProvider(ctx, o.cfg.Issuer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("OIDC discovery failed: %w", err)
+		}
+		o.provider = provider
+	}
+
+	oauthCfg := &oauth2.Config{
+		ClientID:     o.cfg.ClientID,
+		ClientSecret: o.cfg.ClientSecret,
+		Endpoint:     o.provider.Endpoint(),
+		RedirectURL:  o.cfg.RedirectURL,
+		Scopes:       strings.Fields(o.cfg.Scopes),
+	}
+	return o.provider, oauthCfg, nil
+}
+
+// allowed reports whether the subject, or the verified email, is listed in
+// OIDC_ALLOWED_SUBJECTS or OIDC_ALLOWED_EMAILS.
+func (o *oidcClient) allowed(subject, email string, emailVerified bool) bool {
+	if containsString(splitList(o.cfg.AllowedSubjects), subject) {
+		return true
+	}
+	if email == "" || !emailVerified {
+		return false
+	}
+	for _, allowed := range splitList(o.cfg.AllowedEmails) {
+		if strings.EqualFold(allowed, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// StartOIDCLogin creates an authorization request with a fresh state, nonce
+// and PKCE verifier, and returns the provider URL to redirect the browser to.
+func (s *service) StartOIDCLogin() (*OIDCAuthorization, error) {
+	if !s.oidc.enabled() {
+		return nil, ErrOIDCDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+
+	_, oauthCfg, err := s.oidc.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	loginState := &OIDCLoginState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := s.repo.CreateOIDCLoginState(loginState); err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{
+		URL:       oauthCfg.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)),
+		State:     state,
+		ExpiresIn: int64(oidcStateTTL.Seconds()),
+	}, nil
+}
+
+// CompleteOIDCLogin exchanges the authorization code, verifies the ID token
+// and its nonce, and signs in the user bound to the token's subject. A user
+// without a subject is bound on the first login with a verified email. The
+// provider is trusted for the second factor, so no TOTP challenge is issued.
+func (s *service) CompleteOIDCLogin(code, state string, client ClientInfo) (*LoginResult, error) {
+	if !s.oidc.enabled() {
+		return nil, ErrOIDCDisabled
+	}
+
+	loginState, err := s.repo.ConsumeOIDCLoginState(hashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if loginState == nil || time.Now().After(loginState.ExpiresAt) {
+		return nil, ErrOIDCInvalidState
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+
+	provider, oauthCfg, err := s.oidc.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauthCfg.Exchange(ctx, code, oauth2.VerifierOption(loginState.CodeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("OIDC code exchange failed: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("OIDC provider returned no id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.cfg.OIDC.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC id_token: %w", err)
+	}
+	if idToken.Nonce != loginState.Nonce {
+		return nil, errors.New("invalid OIDC id_token: nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	emailVerified := claims.EmailVerified != nil && *claims.EmailVerified
+
+	if !s.oidc.allowed(idToken.Subject, claims.Email, emailVerified) {
+		return nil, ErrOIDCNotAllowed
+	}
+
+	user, err := s.findOIDCUser(idToken.Subject, claims.Email, emailVerified)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// findOIDCUser returns the user bound to the subject. Otherwise a verified
+// email selects the user, who is then bound to the subject; an unverified
+// email never does, and neither does a user bound to another subject.
+func (s *service) findOIDCUser(subject, email string, emailVerified bool) (*User, error) {
+	user, err := s.repo.FindByOIDCSubject(subject)
+	if err != nil || user != nil {
+		return user, err
+	}
+
+	if email == "" || !emailVerified {
+		return nil, ErrOIDCNotAllowed
+	}
+	user, err = s.repo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.OIDCSubject != nil {
+		return nil, ErrOIDCNotAllowed
+	}
+
+	linked, err := s.repo.LinkOIDCSubject(user.ID, subject)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, ErrOIDCNotAllowed
+	}
+	user.OIDCSubject = &subject
+	return user, nil
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+)
+
+const testClientID = "personal-backend"
+
+// testIssuer is a minimal OpenID provider: discovery, JWKS and a token
+// endpoint that signs whatever claims the test sets.
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                issuer.server.URL,
+			"authorization_endpoint":                issuer.server.URL + "/authorize",
+			"token_endpoint":                        issuer.server.URL + "/token",
+			"jwks_uri":                              issuer.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code_verifier") == "" {
+			http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.claims)
+		token.Header["kid"] = "test"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// fakeOIDCRepo keeps the users and login states needed for an OIDC login in
+// memory. Other repository methods are not used and panic.
+type fakeOIDCRepo struct {
+	Repository
+	users    []*User
+	states   map[string]*OIDCLoginState
+	sessions int
+}
+
+func (r *fakeOIDCRepo) CreateOIDCLoginState(state *OIDCLoginState) error {
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeOIDCRepo) ConsumeOIDCLoginState(stateHash string) (*OIDCLoginState, error) {
+	state := r.states[stateHash]
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+func (r *fakeOIDCRepo) FindByOIDCSubject(subject string) (*User, error) {
+	for _, user := range r.users {
+		if user.OIDCSubject != nil && *user.OIDCSubject == subject {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOIDCRepo) FindByEmail(email string) (*User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOIDCRepo) LinkOIDCSubject(userID uuid.UUID, subject string) (bool, error) {
+	for _, user := range r.users {
+		if user.ID == userID && user.OIDCSubject == nil {
+			user.OIDCSubject = &subject
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeOIDCRepo) CreateSession(session *Session) error {
+	r.sessions++
+	return nil
+}
+
+func (r *fakeOIDCRepo) CreateRefreshToken(token *RefreshToken) error {
+	return nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		boundTo     *string // subject already bound to the owner
+		subject     string
+		email       string
+		verified    bool
+		nonce       string // defaults to the nonce of the authorization request
+		wantErr     error
+		wantErrText string
+		wantBound   string
+	}{
+		{
+			name:      "bound subject",
+			boundTo:   stringPtr("sub-owner"),
+			subject:   "sub-owner",
+			wantBound: "sub-owner",
+		},
+		{
+			name:      "verified email binds the subject",
+			subject:   "sub-owner",
+			email:     "owner@example.com",
+			verified:  true,
+			wantBound: "sub-owner",
+		},
+		{
+			name:     "allowed subject with unverified owner email",
+			subject:  "sub-attacker",
+			email:    "owner@example.com",
+			verified: false,
+			wantErr:  ErrOIDCNotAllowed,
+		},
+		{
+			name:      "subject mismatch",
+			boundTo:   stringPtr("sub-owner"),
+			subject:   "sub-other",
+			email:     "owner@example.com",
+			verified:  true,
+			wantErr:   ErrOIDCNotAllowed,
+			wantBound: "sub-owner",
+		},
+		{
+			name:     "email not allowed",
+			subject:  "sub-unknown",
+			email:    "owner@example.org",
+			verified: true,
+			wantErr:  ErrOIDCNotAllowed,
+		},
+		{
+			name:     "email without account",
+			subject:  "sub-other",
+			email:    "stranger@example.com",
+			verified: true,
+			wantErr:  ErrOIDCNotAllowed,
+		},
+		{
+			name:        "nonce mismatch",
+			boundTo:     stringPtr("sub-owner"),
+			subject:     "sub-owner",
+			nonce:       "replayed-nonce",
+			wantErrText: "nonce mismatch",
+			wantBound:   "sub-owner",
+		},
+	}
+
+	issuer := newTestIssuer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := &User{ID: uuid.New(), Email: "owner@example.com", Role: RoleOwner, OIDCSubject: tt.boundTo}
+			repo := &fakeOIDCRepo{users: []*User{owner}, states: map[string]*OIDCLoginState{}}
+
+			cfg := &config.Config{
+				JWT:      config.JWTConfig{Secret: "test-secret", Expiration: 15, RefreshExpiration: 24},
+				WebAuthn: config.WebAuthnConfig{RPID: testRPID, RPDisplayName: "Personal Backend", RPOrigins: testOrigin},
+				OIDC: config.OIDCConfig{
+					Issuer:          issuer.server.URL,
+					ClientID:        testClientID,
+					RedirectURL:     "http://localhost:3000/admin/oidc/callback",
+					Scopes:          "openid email",
+					AllowedSubjects: "sub-owner,sub-attacker,sub-other",
+					AllowedEmails:   "owner@example.com,stranger@example.com",
+				},
+			}
+			keys, err := keyring.Load(cfg.JWT)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc := NewService(repo, cfg, nil, keys, nil)
+
+			authorization, err := svc.StartOIDCLogin()
+			if err != nil {
+				t.Fatalf("StartOIDCLogin: %v", err)
+			}
+			authURL, err := url.Parse(authorization.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = authURL.Query().Get("nonce")
+			}
+
+			now := time.Now()
+			issuer.claims = jwt.MapClaims{
+				"iss":            issuer.server.URL,
+				"aud":            testClientID,
+				"sub":            tt.subject,
+				"nonce":          nonce,
+				"iat":            now.Unix(),
+				"exp":            now.Add(time.Minute).Unix(),
+				"email":          tt.email,
+				"email_verified": tt.verified,
+			}
+
+			result, err := svc.CompleteOIDCLogin("code", authorization.State, ClientInfo{IP: "192.0.2.1"})
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantErrText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrText)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("CompleteOIDCLogin: %v", err)
+				}
+				if result.User.ID != owner.ID || result.Tokens == nil {
+					t.Fatalf("signed in as %v, want the owner", result.User.ID)
+				}
+			}
+
+			wantSessions := 0
+			if tt.wantErr == nil && tt.wantErrText == "" {
+				wantSessions = 1
+			}
+			if repo.sessions != wantSessions {
+				t.Errorf("sessions = %d, want %d", repo.sessions, wantSessions)
+			}
+
+			bound := ""
+			if owner.OIDCSubject != nil {
+				bound = *owner.OIDCSubject
+			}
+			if bound != tt.wantBound {
+				t.Errorf("owner bound to %q, want %q", bound, tt.wantBound)
+			}
+
+			if _, err := svc.CompleteOIDCLogin("code", authorization.State, ClientInfo{}); !errors.Is(err, ErrOIDCInvalidState) {
+				t.Errorf("second callback error = %v, want %v", err, ErrOIDCInvalidState)
+			}
+		})
+	}
+}
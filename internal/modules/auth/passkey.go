@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+)
+
+const (
+	passkeyCeremonyTTL      = 5 * time.Minute
+	passkeyCeremonyRegister = "registration"
+	passkeyCeremonyLogin    = "login"
+	defaultPasskeyName      = "Passkey"
+)
+
+var (
+	ErrPasskeysUnavailable = errors.New("passkeys are not configured")
+	ErrInvalidCeremony     = errors.New("invalid or expired passkey ceremony")
+	ErrInvalidPasskey      = errors.New("passkey verification failed")
+	ErrPasskeyNotFound     = errors.New("passkey not found")
+)
+
+// PasskeyCeremonyStart carries the options for navigator.credentials.create()
+// or .get() and the token that identifies the ceremony when it is finished.
+type PasskeyCeremonyStart struct {
+	Token     string
+	Options   interface{}
+	ExpiresIn int64 // seconds
+}
+
+// webauthnUser adapts a User and its passkeys to webauthn.User. The user
+// handle is the user's UUID, which reveals nothing about the account.
+type webauthnUser struct {
+	user     *User
+	passkeys []Passkey
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	id := u.user.ID
+	return id[:]
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if u.user.Profile != nil && u.user.Profile.FullName != "" {
+		return u.user.Profile.FullName
+	}
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for i := range u.passkeys {
+		credentials = append(credentials, u.passkeys[i].credential())
+	}
+	return credentials
+}
+
+func (p *Passkey) credential() webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(p.Transports))
+	for _, t := range p.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(t))
+	}
+
+	return webauthn.Credential{
+		ID:              p.CredentialID,
+		PublicKey:       p.PublicKey,
+		AttestationType: p.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: p.BackupEligible,
+			BackupState:    p.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    p.AAGUID,
+			SignCount: uint32(p.SignCount),
+		},
+	}
+}
+
+func newWebAuthn(cfg config.WebAuthnConfig) *webauthn.WebAuthn {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     splitList(cfg.RPOrigins),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		log.Printf("Passkeys disabled: %v", err)
+		return nil
+	}
+	return w
+}
+
+func (s *service) loadWebAuthnUser(userID uuid.UUID) (*webauthnUser, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+	passkeys, err := s.repo.FindPasskeysByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthnUser{user: user, passkeys: passkeys}, nil
+}
+
+func (s *service) startPasskeyCeremony(kind string, userID *uuid.UUID, session *webauthn.SessionData, options interface{}) (*PasskeyCeremonyStart, error) {
+	token, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony := &PasskeyCeremony{
+		TokenHash: hashToken(token),
+		Kind:      kind,
+		UserID:    userID,
+		Session:   *session,
+		ExpiresAt: time.Now().Add(passkeyCeremonyTTL),
+	}
+	if err := s.repo.CreatePasskeyCeremony(ceremony); err != nil {
+		return nil, err
+	}
+
+	return &PasskeyCeremonyStart{
+		Token:     token,
+		Options:   options,
+		ExpiresIn: int64(passkeyCeremonyTTL.Seconds()),
+	}, nil
+}
+
+func (s *service) consumePasskeyCeremony(token, kind string) (*PasskeyCeremony, error) {
+	ceremony, err := s.repo.ConsumePasskeyCeremony(hashToken(token), kind)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony == nil || time.Now().After(ceremony.ExpiresAt) {
+		return nil, ErrInvalidCeremony
+	}
+	return ceremony, nil
+}
+
+// BeginPasskeyRegistration returns creation options for a new passkey of the
+// user. Passkeys the user already has are excluded.
+func (s *service) BeginPasskeyRegistration(userID uuid.UUID) (*PasskeyCeremonyStart, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeysUnavailable
+	}
+
+	wu, err := s.loadWebAuthnUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if wu == nil {
+		return nil, errors.New("user not found")
+	}
+
+	exclusions := webauthn.Credentials(wu.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := s.webauthn.BeginRegistration(wu, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startPasskeyCeremony(passkeyCeremonyRegister, &userID, session, creation)
+}
+
+// FinishPasskeyRegistration verifies the attestation response and stores the
+// new credential.
+func (s *service) FinishPasskeyRegistration(actor audit.Actor, userID uuid.UUID, token, name string, response []byte) (*Passkey, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeysUnavailable
+	}
+
+	ceremony, err := s.consumePasskeyCeremony(token, passkeyCeremonyRegister)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID == nil || *ceremony.UserID != userID {
+		return nil, ErrInvalidCeremony
+	}
+
+	wu, err := s.loadWebAuthnUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if wu == nil {
+		return nil, errors.New("user not found")
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	credential, err := s.webauthn.CreateCredential(wu, ceremony.Session, parsed)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+
+	passkey := &Passkey{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := s.repo.CreatePasskey(passkey); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionCreate, "passkey", passkey.ID.String(), nil, map[string]interface{}{
+		"UserID": passkey.UserID,
+		"Name":   passkey.Name,
+	})
+	return passkey, nil
+}
+
+func (s *service) ListPasskeys(userID uuid.UUID) ([]Passkey, error) {
+	return s.repo.FindPasskeysByUser(userID)
+}
+
+func (s *service) DeletePasskey(actor audit.Actor, userID, passkeyID uuid.UUID) error {
+	deleted, err := s.repo.DeletePasskey(passkeyID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPasskeyNotFound
+	}
+
+	s.audit.Record(actor, audit.ActionDelete, "passkey", passkeyID.String(), nil, nil)
+	return nil
+}
+
+// BeginPasskeyLogin returns assertion options for any discoverable passkey of
+// this site. No credentials are listed, so the response is the same whether or
+// not an account exists; the passkey itself identifies the user.
+func (s *service) BeginPasskeyLogin() (*PasskeyCeremonyStart, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeysUnavailable
+	}
+
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, err
+	}
+	return s.startPasskeyCeremony(passkeyCeremonyLogin, nil, session, assertion)
+}
+
+// FinishPasskeyLogin verifies the assertion and issues the same tokens as a
+// password login. A passkey with user verification counts as two factors, so
+// no TOTP challenge follows. A signature counter that went backwards points to
+// a cloned authenticator and is rejected.
+func (s *service) FinishPasskeyLogin(token string, response []byte, client ClientInfo) (*LoginResult, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeysUnavailable
+	}
+
+	ceremony, err := s.consumePasskeyCeremony(token, passkeyCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	var wu *webauthnUser
+	credential, err := s.webauthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		wu, err = s.loadWebAuthnUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		if wu == nil {
+			return nil, ErrInvalidPasskey
+		}
+		return wu, nil
+	}, ceremony.Session, parsed)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	if credential.Authenticator.CloneWarning {
+		return nil, ErrInvalidPasskey
+	}
+
+	passkey, err := s.repo.FindPasskeyByCredentialID(credential.ID)
+	if err != nil {
+		return nil, err
+	}
+	if passkey == nil || passkey.UserID != wu.user.ID {
+		return nil, ErrInvalidPasskey
+	}
+	if err := s.repo.UpdatePasskeyUsage(passkey.ID, int64(credential.Authenticator.SignCount), credential.Flags.BackupState, time.Now()); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(wu.user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens, User: wu.user}, nil
+}
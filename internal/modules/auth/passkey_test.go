@@ -0,0 +1,363 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+// Authenticator data flags, see https://www.w3.org/TR/webauthn-2/#flags
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// softAuthenticator is a software authenticator with one ES256 credential. It
+// produces the same attestation ("none") and assertion responses a browser
+// posts after navigator.credentials.create() and .get().
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+	origin       string
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialID := make([]byte, 16)
+	rand.Read(credentialID)
+	return &softAuthenticator{key: key, credentialID: credentialID, origin: testOrigin}
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, kind string, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]string{
+		"type":      kind,
+		"challenge": challenge.String(),
+		"origin":    a.origin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+// create answers creation options with a new credential for the user.
+func (a *softAuthenticator) create(t *testing.T, options interface{}) []byte {
+	t.Helper()
+	creation := options.(*protocol.CredentialCreation)
+	a.userHandle = creation.Response.User.ID.(protocol.URLEncodedBase64)
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authData := a.authData(flagUserPresent | flagUserVerified | flagAttestedData)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    b64(a.clientData(t, "webauthn.create", creation.Response.Challenge)),
+			"attestationObject": b64(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// get answers assertion options, signing with the credential.
+func (a *softAuthenticator) get(t *testing.T, options interface{}) []byte {
+	t.Helper()
+	assertion := options.(*protocol.CredentialAssertion)
+
+	a.signCount++
+	authData := a.authData(flagUserPresent | flagUserVerified)
+	clientData := a.clientData(t, "webauthn.get", assertion.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    b64(clientData),
+			"authenticatorData": b64(authData),
+			"signature":         b64(signature),
+			"userHandle":        b64(a.userHandle),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// fakePasskeyRepo keeps users, passkeys and ceremonies in memory. Other
+// repository methods are not used and panic.
+type fakePasskeyRepo struct {
+	Repository
+	users      map[uuid.UUID]*User
+	passkeys   []Passkey
+	ceremonies map[string]*PasskeyCeremony
+	sessions   int
+}
+
+func (r *fakePasskeyRepo) FindByID(id uuid.UUID) (*User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakePasskeyRepo) CreatePasskey(passkey *Passkey) error {
+	passkey.ID = uuid.New()
+	r.passkeys = append(r.passkeys, *passkey)
+	return nil
+}
+
+func (r *fakePasskeyRepo) FindPasskeysByUser(userID uuid.UUID) ([]Passkey, error) {
+	var passkeys []Passkey
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userID {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *fakePasskeyRepo) FindPasskeyByCredentialID(credentialID []byte) (*Passkey, error) {
+	for i := range r.passkeys {
+		if bytes.Equal(r.passkeys[i].CredentialID, credentialID) {
+			return &r.passkeys[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePasskeyRepo) UpdatePasskeyUsage(id uuid.UUID, signCount int64, backupState bool, usedAt time.Time) error {
+	for i := range r.passkeys {
+		if r.passkeys[i].ID == id {
+			r.passkeys[i].SignCount = signCount
+			r.passkeys[i].BackupState = backupState
+			r.passkeys[i].LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakePasskeyRepo) CreatePasskeyCeremony(ceremony *PasskeyCeremony) error {
+	r.ceremonies[ceremony.TokenHash] = ceremony
+	return nil
+}
+
+func (r *fakePasskeyRepo) ConsumePasskeyCeremony(tokenHash, kind string) (*PasskeyCeremony, error) {
+	ceremony := r.ceremonies[tokenHash]
+	if ceremony == nil || ceremony.Kind != kind {
+		return nil, nil
+	}
+	delete(r.ceremonies, tokenHash)
+	return ceremony, nil
+}
+
+func (r *fakePasskeyRepo) CreateSession(session *Session) error {
+	r.sessions++
+	return nil
+}
+
+func (r *fakePasskeyRepo) CreateRefreshToken(token *RefreshToken) error {
+	return nil
+}
+
+type nopAudit struct {
+	audit.Service
+}
+
+func (nopAudit) Record(actor audit.Actor, action, entityType, entityID string, before, after interface{}) {
+}
+
+// newPasskeyTest returns a service for one user with a passkey registered
+// through the software authenticator.
+func newPasskeyTest(t *testing.T) (Service, *fakePasskeyRepo, *User, *softAuthenticator) {
+	t.Helper()
+	user := &User{ID: uuid.New(), Email: "owner@example.com", Role: RoleOwner}
+	repo := &fakePasskeyRepo{
+		users:      map[uuid.UUID]*User{user.ID: user},
+		ceremonies: map[string]*PasskeyCeremony{},
+	}
+
+	cfg := &config.Config{
+		JWT:      config.JWTConfig{Secret: "test-secret", Expiration: 15, RefreshExpiration: 24},
+		WebAuthn: config.WebAuthnConfig{RPID: testRPID, RPDisplayName: "Personal Backend", RPOrigins: testOrigin},
+	}
+	keys, err := keyring.Load(cfg.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(repo, cfg, nil, keys, nopAudit{})
+
+	authenticator := newSoftAuthenticator(t)
+	start, err := svc.BeginPasskeyRegistration(user.ID)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	passkey, err := svc.FinishPasskeyRegistration(audit.Actor{}, user.ID, start.Token, "", authenticator.create(t, start.Options))
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+	if passkey.Name != defaultPasskeyName || !bytes.Equal(passkey.CredentialID, authenticator.credentialID) {
+		t.Fatalf("stored passkey %q with credential %x", passkey.Name, passkey.CredentialID)
+	}
+	return svc, repo, user, authenticator
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	svc, repo, user, authenticator := newPasskeyTest(t)
+
+	start, err := svc.BeginPasskeyLogin()
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	if allowed := start.Options.(*protocol.CredentialAssertion).Response.AllowedCredentials; len(allowed) != 0 {
+		t.Fatalf("options list %d credentials, want none", len(allowed))
+	}
+
+	response := authenticator.get(t, start.Options)
+	result, err := svc.FinishPasskeyLogin(start.Token, response, ClientInfo{})
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	if result.User.ID != user.ID || repo.sessions != 1 {
+		t.Fatalf("signed in as %v with %d sessions", result.User.ID, repo.sessions)
+	}
+	if repo.passkeys[0].SignCount != 1 || repo.passkeys[0].LastUsedAt == nil {
+		t.Errorf("passkey usage not recorded: sign count %d", repo.passkeys[0].SignCount)
+	}
+
+	if _, err := svc.FinishPasskeyLogin(start.Token, response, ClientInfo{}); !errors.Is(err, ErrInvalidCeremony) {
+		t.Errorf("replayed ceremony error = %v, want %v", err, ErrInvalidCeremony)
+	}
+}
+
+func TestPasskeyLoginRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(a *softAuthenticator)
+	}{
+		{
+			name:   "cloned authenticator",
+			tamper: func(a *softAuthenticator) { a.signCount = 0 },
+		},
+		{
+			name:   "foreign origin",
+			tamper: func(a *softAuthenticator) { a.origin = "https://evil.example" },
+		},
+		{
+			name: "other key",
+			tamper: func(a *softAuthenticator) {
+				a.key, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			},
+		},
+		{
+			name: "unknown user handle",
+			tamper: func(a *softAuthenticator) {
+				id := uuid.New()
+				a.userHandle = id[:]
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _, authenticator := newPasskeyTest(t)
+
+			// A first login moves the stored sign count to 1
+			start, err := svc.BeginPasskeyLogin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.FinishPasskeyLogin(start.Token, authenticator.get(t, start.Options), ClientInfo{}); err != nil {
+				t.Fatalf("first login: %v", err)
+			}
+
+			tt.tamper(authenticator)
+			start, err = svc.BeginPasskeyLogin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = svc.FinishPasskeyLogin(start.Token, authenticator.get(t, start.Options), ClientInfo{})
+			if !errors.Is(err, ErrInvalidPasskey) {
+				t.Fatalf("error = %v, want %v", err, ErrInvalidPasskey)
+			}
+			if repo.sessions != 1 {
+				t.Errorf("sessions = %d, want 1", repo.sessions)
+			}
+		})
+	}
+}
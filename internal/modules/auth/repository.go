@@ -2,16 +2,71 @@ package auth
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	FindByEmail(email string) (*User, error)
 	FindByID(id uuid.UUID) (*User, error)
+	FindByOIDCSubject(subject string) (*User, error)
+	LinkOIDCSubject(userID uuid.UUID, subject string) (bool, error)
 	Update(user *User) error
 	Create(user *User) error
+	Delete(id uuid.UUID) error
+	FindAll() ([]User, error)
+	CountByRole(role string) (int64, error)
+	CreateRefreshToken(token *RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*RefreshToken, error)
+	RevokeRefreshToken(id uuid.UUID, replacedBy *uuid.UUID) (bool, error)
+	CreateSession(session *Session) error
+	FindSessionByID(id uuid.UUID) (*Session, error)
+	FindActiveSessions(userID uuid.UUID) ([]Session, error)
+	TouchSession(id uuid.UUID, seenAt time.Time) error
+	ExtendSession(id uuid.UUID, seenAt, expiresAt time.Time) error
+	RevokeSession(id uuid.UUID) error
+	RevokeUserSessions(userID uuid.UUID, except *uuid.UUID) error
+	RevokeAllSessions() error
+	ReplaceRecoveryCodes(userID uuid.UUID, codes []RecoveryCode) error
+	DeleteRecoveryCodes(userID uuid.UUID) error
+	FindUnusedRecoveryCode(userID uuid.UUID, hash string) (*RecoveryCode, error)
+	MarkRecoveryCodeUsed(id uuid.UUID) (bool, error)
+	UseTOTPStep(userID uuid.UUID, step int64) (bool, error)
+	CreateLoginChallenge(challenge *LoginChallenge) error
+	FindLoginChallengeByHash(hash string) (*LoginChallenge, error)
+	IncrementLoginChallengeAttempts(id uuid.UUID) error
+	DeleteLoginChallenge(id uuid.UUID) error
+	FindLoginThrottles(keys []string) ([]LoginThrottle, error)
+	RecordLoginFailure(key string, windowStart time.Time) (*LoginThrottle, error)
+	BlockLoginThrottle(id uuid.UUID, until time.Time, locked bool) error
+	DeleteLoginThrottleByKey(key string) error
+	DeleteLoginThrottle(id uuid.UUID) error
+	ListLoginThrottles() ([]LoginThrottle, error)
+	CreatePasswordResetToken(token *PasswordResetToken) error
+	FindPasswordResetTokenByHash(hash string) (*PasswordResetToken, error)
+	MarkPasswordResetTokenUsed(id uuid.UUID) (bool, error)
+	InvalidatePasswordResetTokens(userID uuid.UUID) error
+	CreateAPIKey(key *APIKey) error
+	FindAPIKeyByHash(hash string) (*APIKey, error)
+	FindAllAPIKeys() ([]APIKey, error)
+	RevokeAPIKey(id uuid.UUID) (bool, error)
+	TouchAPIKey(id uuid.UUID, usedAt time.Time) error
+	CreateMagicLinkToken(token *MagicLinkToken) error
+	ConsumeMagicLinkToken(tokenHash string) (*MagicLinkToken, error)
+	CreateOIDCLoginState(state *OIDCLoginState) error
+	ConsumeOIDCLoginState(stateHash string) (*OIDCLoginState, error)
+	CreatePasskey(passkey *Passkey) error
+	FindPasskeysByUser(userID uuid.UUID) ([]Passkey, error)
+	FindPasskeyByCredentialID(credentialID []byte) (*Passkey, error)
+	UpdatePasskeyUsage(id uuid.UUID, signCount int64, backupState bool, usedAt time.Time) error
+	DeletePasskey(id, userID uuid.UUID) (bool, error)
+	CreatePasskeyCeremony(ceremony *PasskeyCeremony) error
+	ConsumePasskeyCeremony(tokenHash, kind string) (*PasskeyCeremony, error)
+	FindPasswordHistory(userID uuid.UUID, limit int) ([]PasswordHistory, error)
+	AddPasswordHistory(entry *PasswordHistory, keep int) error
 }
 
 type repository struct {
@@ -46,10 +101,532 @@ func (r *repository) FindByID(id uuid.UUID) (*User, error) {
 	return &user, nil
 }
 
+func (r *repository) FindByOIDCSubject(subject string) (*User, error) {
+	var user User
+	err := r.db.Preload("Profile").Where("oidc_subject = ?", subject).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LinkOIDCSubject binds the subject to a user that has none yet. It reports
+// false when the user is already bound.
+func (r *repository) LinkOIDCSubject(userID uuid.UUID, subject string) (bool, error) {
+	result := r.db.Model(&User{}).
+		Where("id = ? AND oidc_subject IS NULL", userID).
+		Update("oidc_subject", subject)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Update saves the user. The last accepted TOTP step and the OIDC subject are
+// left alone: only UseTOTPStep and LinkOIDCSubject set them, so a stale copy
+// cannot undo them.
 func (r *repository) Update(user *User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("TOTPLastStep", "OIDCSubject").Save(user).Error
 }
 
 func (r *repository) Create(user *User) error {
 	return r.db.Create(user).Error
 }
+
+// Delete removes the user together with their tokens and recovery codes.
+func (r *repository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&LoginChallenge{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&Passkey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&PasskeyCeremony{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&MagicLinkToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&User{}, "id = ?", id).Error
+	})
+}
+
+func (r *repository) FindAll() ([]User, error) {
+	var users []User
+	err := r.db.Preload("Profile").Order("created_at ASC").Find(&users).Error
+	return users, err
+}
+
+func (r *repository) CountByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreateRefreshToken(token *RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *repository) FindRefreshTokenByHash(hash string) (*RefreshToken, error) {
+	var token RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken marks a token as revoked only if it is still active.
+// It reports whether this call revoked it, so concurrent rotations of the
+// same token cannot both succeed.
+func (r *repository) RevokeRefreshToken(id uuid.UUID, replacedBy *uuid.UUID) (bool, error) {
+	result := r.db.Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":  time.Now(),
+			"replaced_by": replacedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) CreateSession(session *Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *repository) FindSessionByID(id uuid.UUID) (*Session, error) {
+	var session Session
+	err := r.db.First(&session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *repository) FindActiveSessions(userID uuid.UUID) ([]Session, error) {
+	var sessions []Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *repository) TouchSession(id uuid.UUID, seenAt time.Time) error {
+	return r.db.Model(&Session{}).Where("id = ?", id).Update("last_seen_at", seenAt).Error
+}
+
+func (r *repository) ExtendSession(id uuid.UUID, seenAt, expiresAt time.Time) error {
+	return r.db.Model(&Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_seen_at": seenAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// RevokeSession ends the session and every refresh token issued for it.
+func (r *repository) RevokeSession(id uuid.UUID) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Session{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+	})
+}
+
+// RevokeUserSessions ends all sessions of the user, optionally keeping one.
+func (r *repository) RevokeUserSessions(userID uuid.UUID, except *uuid.UUID) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sessions := tx.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		tokens := tx.Model(&RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		if except != nil {
+			sessions = sessions.Where("id <> ?", *except)
+			tokens = tokens.Where("family_id <> ?", *except)
+		}
+		if err := sessions.Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tokens.Update("revoked_at", now).Error
+	})
+}
+
+// RevokeAllSessions logs every user out of every session.
+func (r *repository) RevokeAllSessions() error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Session{}).Where("revoked_at IS NULL").Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&RefreshToken{}).Where("revoked_at IS NULL").Update("revoked_at", now).Error
+	})
+}
+
+func (r *repository) ReplaceRecoveryCodes(userID uuid.UUID, codes []RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *repository) DeleteRecoveryCodes(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+}
+
+func (r *repository) FindUnusedRecoveryCode(userID uuid.UUID, hash string) (*RecoveryCode, error) {
+	var code RecoveryCode
+	err := r.db.Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+// UseTOTPStep records that a code of the given time step was accepted. It
+// reports false when a code of that step or a later one was accepted before.
+func (r *repository) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) MarkRecoveryCodeUsed(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) CreateLoginChallenge(challenge *LoginChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *repository) FindLoginChallengeByHash(hash string) (*LoginChallenge, error) {
+	var challenge LoginChallenge
+	err := r.db.Where("token_hash = ?", hash).First(&challenge).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *repository) IncrementLoginChallengeAttempts(id uuid.UUID) error {
+	return r.db.Model(&LoginChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *repository) DeleteLoginChallenge(id uuid.UUID) error {
+	return r.db.Delete(&LoginChallenge{}, "id = ?", id).Error
+}
+
+func (r *repository) FindLoginThrottles(keys []string) ([]LoginThrottle, error) {
+	var throttles []LoginThrottle
+	err := r.db.Where("key IN ?", keys).Find(&throttles).Error
+	return throttles, err
+}
+
+// RecordLoginFailure atomically increments the failure counter for key,
+// starting over when the previous failure happened before windowStart.
+func (r *repository) RecordLoginFailure(key string, windowStart time.Time) (*LoginThrottle, error) {
+	var throttle LoginThrottle
+	now := time.Now()
+	err := r.db.Raw(`
+		INSERT INTO login_throttles (key, failures, last_failure_at, created_at)
+		VALUES (?, 1, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			locked = CASE WHEN login_throttles.last_failure_at < ? THEN FALSE ELSE login_throttles.locked END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`,
+		key, now, now, windowStart, windowStart,
+	).Scan(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *repository) BlockLoginThrottle(id uuid.UUID, until time.Time, locked bool) error {
+	return r.db.Model(&LoginThrottle{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"blocked_until": until,
+			"locked":        locked,
+		}).Error
+}
+
+func (r *repository) DeleteLoginThrottleByKey(key string) error {
+	return r.db.Where("key = ?", key).Delete(&LoginThrottle{}).Error
+}
+
+func (r *repository) DeleteLoginThrottle(id uuid.UUID) error {
+	return r.db.Delete(&LoginThrottle{}, "id = ?", id).Error
+}
+
+func (r *repository) ListLoginThrottles() ([]LoginThrottle, error) {
+	var throttles []LoginThrottle
+	err := r.db.Order("last_failure_at DESC").Find(&throttles).Error
+	return throttles, err
+}
+
+func (r *repository) CreatePasswordResetToken(token *PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *repository) FindPasswordResetTokenByHash(hash string) (*PasswordResetToken, error) {
+	var token PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *repository) MarkPasswordResetTokenUsed(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidatePasswordResetTokens marks every outstanding reset token of the user as used.
+func (r *repository) InvalidatePasswordResetTokens(userID uuid.UUID) error {
+	return r.db.Model(&PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *repository) CreateAPIKey(key *APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *repository) FindAPIKeyByHash(hash string) (*APIKey, error) {
+	var key APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *repository) FindAllAPIKeys() ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *repository) RevokeAPIKey(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) TouchAPIKey(id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
+
+// CreateMagicLinkToken stores a new magic link and purges expired ones.
+// Earlier links of the user stay valid: each is bound to the nonce of the
+// browser that asked for it, so a request by someone else cannot cancel it.
+func (r *repository) CreateMagicLinkToken(token *MagicLinkToken) error {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&MagicLinkToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.Create(token).Error
+}
+
+// ConsumeMagicLinkToken deletes and returns the token so that a link can be
+// used only once. It also purges expired links.
+func (r *repository) ConsumeMagicLinkToken(tokenHash string) (*MagicLinkToken, error) {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&MagicLinkToken{}).Error; err != nil {
+		return nil, err
+	}
+
+	var tokens []MagicLinkToken
+	result := r.db.Clauses(clause.Returning{}).Where("token_hash = ?", tokenHash).Delete(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return &tokens[0], nil
+}
+
+func (r *repository) CreateOIDCLoginState(state *OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeOIDCLoginState deletes and returns the state, so that a callback can
+// be completed only once. It also purges expired states.
+func (r *repository) ConsumeOIDCLoginState(stateHash string) (*OIDCLoginState, error) {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&OIDCLoginState{}).Error; err != nil {
+		return nil, err
+	}
+
+	var states []OIDCLoginState
+	result := r.db.Clauses(clause.Returning{}).Where("state_hash = ?", stateHash).Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+func (r *repository) CreatePasskey(passkey *Passkey) error {
+	return r.db.Create(passkey).Error
+}
+
+func (r *repository) FindPasskeysByUser(userID uuid.UUID) ([]Passkey, error) {
+	var passkeys []Passkey
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&passkeys).Error
+	return passkeys, err
+}
+
+func (r *repository) FindPasskeyByCredentialID(credentialID []byte) (*Passkey, error) {
+	var passkey Passkey
+	err := r.db.Where("credential_id = ?", credentialID).First(&passkey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+func (r *repository) UpdatePasskeyUsage(id uuid.UUID, signCount int64, backupState bool, usedAt time.Time) error {
+	return r.db.Model(&Passkey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"backup_state": backupState,
+			"last_used_at": usedAt,
+		}).Error
+}
+
+func (r *repository) DeletePasskey(id, userID uuid.UUID) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Passkey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CreatePasskeyCeremony stores the ceremony and purges expired ones, so that
+// ceremonies which are begun but never finished do not pile up.
+func (r *repository) CreatePasskeyCeremony(ceremony *PasskeyCeremony) error {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&PasskeyCeremony{}).Error; err != nil {
+		return err
+	}
+	return r.db.Create(ceremony).Error
+}
+
+// ConsumePasskeyCeremony deletes and returns the ceremony so that it can be
+// finished only once. It also purges expired ceremonies.
+func (r *repository) ConsumePasskeyCeremony(tokenHash, kind string) (*PasskeyCeremony, error) {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&PasskeyCeremony{}).Error; err != nil {
+		return nil, err
+	}
+
+	var ceremonies []PasskeyCeremony
+	result := r.db.Clauses(clause.Returning{}).
+		Where("token_hash = ? AND kind = ?", tokenHash, kind).
+		Delete(&ceremonies)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(ceremonies) == 0 {
+		return nil, nil
+	}
+	return &ceremonies[0], nil
+}
+
+// FindPasswordHistory returns the user's most recent previous password hashes.
+func (r *repository) FindPasswordHistory(userID uuid.UUID, limit int) ([]PasswordHistory, error) {
+	var entries []PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// AddPasswordHistory stores a previous hash and deletes all but the newest keep entries.
+func (r *repository) AddPasswordHistory(entry *PasswordHistory, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id NOT IN (?)", entry.UserID,
+			tx.Model(&PasswordHistory{}).
+				Select("id").
+				Where("user_id = ?", entry.UserID).
+				Order("created_at DESC").
+				Limit(keep),
+		).Delete(&PasswordHistory{}).Error
+	})
+}
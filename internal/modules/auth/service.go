@@ -1,62 +1,775 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
 	"github.com/prakoso-id/personal-backend/internal/config"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+	"github.com/prakoso-id/personal-backend/internal/mailer"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/password"
 )
 
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	maxLoginChallengeAttempts = 5
+	recoveryCodeCount         = 10
+	maxLoginBlock             = 24 * time.Hour
+	apiKeyPrefix              = "pk_"
+	apiKeyTouchInterval       = time.Minute
+	sessionTouchInterval      = time.Minute
+	maxUserAgentLength        = 512
+	totpPeriod                = 30 // seconds per TOTP time step
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidChallenge    = errors.New("invalid or expired login challenge")
+	ErrInvalidTOTPCode     = errors.New("invalid two-factor code")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrLastOwner           = errors.New("cannot remove the last owner")
+	ErrInvalidResetToken   = errors.New("invalid or expired reset token")
+	ErrInvalidAPIKey       = errors.New("invalid API key")
+	ErrInvalidSession      = errors.New("session expired or revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrAccountDisabled     = errors.New("account is disabled")
+)
+
+// ThrottleError reports that login attempts for the email or client IP are
+// temporarily refused. Err is set when the attempt was evaluated and failed,
+// and is nil when it was rejected before the credentials were checked.
+type ThrottleError struct {
+	RetryAfter time.Duration
+	Locked     bool
+	Err        error
+}
+
+func (e *ThrottleError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	if e.Locked {
+		return "too many failed login attempts, account temporarily locked"
+	}
+	return "too many failed login attempts, try again later"
+}
+
+func (e *ThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError reports that too many emails were requested for an address
+// or from a client IP.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return "too many requests, try again later"
+}
+
 type Service interface {
-	Login(email, password string) (string, *User, error)
-	UpdateEmail(userID uuid.UUID, newEmail string) error
-	UpdatePassword(userID uuid.UUID, newPassword string) error
+	Login(email, password string, client ClientInfo) (*LoginResult, error)
+	CompleteTwoFactorLogin(challengeToken, code string, client ClientInfo) (*LoginResult, error)
+	StartOIDCLogin() (*OIDCAuthorization, error)
+	CompleteOIDCLogin(code, state string, client ClientInfo) (*LoginResult, error)
+	RequestMagicLink(email string, client ClientInfo) (*MagicLinkRequest, error)
+	CompleteMagicLinkLogin(token, nonce string, client ClientInfo) (*LoginResult, error)
+	BeginPasskeyLogin() (*PasskeyCeremonyStart, error)
+	FinishPasskeyLogin(token string, response []byte, client ClientInfo) (*LoginResult, error)
+	BeginPasskeyRegistration(userID uuid.UUID) (*PasskeyCeremonyStart, error)
+	FinishPasskeyRegistration(actor audit.Actor, userID uuid.UUID, token, name string, response []byte) (*Passkey, error)
+	ListPasskeys(userID uuid.UUID) ([]Passkey, error)
+	DeletePasskey(actor audit.Actor, userID, passkeyID uuid.UUID) error
+	Refresh(refreshToken string) (*TokenPair, error)
+	Logout(refreshToken string) error
+	ValidateSession(userID, sessionID uuid.UUID) error
+	ListSessions(userID uuid.UUID) ([]Session, error)
+	RevokeSession(actor audit.Actor, userID, sessionID uuid.UUID) error
+	RevokeAllSessions(actor audit.Actor, userID uuid.UUID) error
+	RevokeEverySession(actor audit.Actor) error
+	UpdateEmail(actor audit.Actor, userID uuid.UUID, newEmail string) error
+	UpdatePassword(actor audit.Actor, userID, currentSessionID uuid.UUID, newPassword string) error
+	SetupTOTP(userID uuid.UUID) (*TOTPSetup, error)
+	EnableTOTP(actor audit.Actor, userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(actor audit.Actor, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(actor audit.Actor, userID uuid.UUID, code string) ([]string, error)
+	ListLoginThrottles() ([]LoginThrottle, error)
+	ClearLoginThrottle(actor audit.Actor, id uuid.UUID) error
+	ListUsers() ([]User, error)
+	InviteUser(actor audit.Actor, email, role string) (*User, string, error)
+	CreateUser(actor audit.Actor, email, role, plainPassword string) (*User, error)
+	SetUserPassword(actor audit.Actor, userID uuid.UUID, newPassword string) (string, error)
+	DisableUser(actor audit.Actor, userID uuid.UUID) error
+	EnableUser(actor audit.Actor, userID uuid.UUID) error
+	UpdateUserRole(actor audit.Actor, userID uuid.UUID, role string) (*User, error)
+	DeleteUser(actor audit.Actor, userID uuid.UUID) error
+	RequestPasswordReset(email string, client ClientInfo) error
+	ResetPassword(token, newPassword string) error
+	CreateAPIKey(actor audit.Actor, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error)
+	ListAPIKeys() ([]APIKey, error)
+	RevokeAPIKey(actor audit.Actor, id uuid.UUID) error
+	AuthenticateAPIKey(key string) (*APIKey, []string, error)
+	JWKS() keyring.JWKSet
 }
 
 type service struct {
-	repo Repository
-	cfg  *config.Config
+	repo     Repository
+	cfg      *config.Config
+	mailer   mailer.Mailer
+	keys     *keyring.Keyring
+	oidc     *oidcClient
+	webauthn *webauthn.WebAuthn // nil when the relying party config is invalid
+	hasher   *password.Hasher
+	policy   *password.Policy
+	audit    audit.Service
 }
 
-func NewService(repo Repository, cfg *config.Config) Service {
-	return &service{repo: repo, cfg: cfg}
+func NewService(repo Repository, cfg *config.Config, mail mailer.Mailer, keys *keyring.Keyring, auditService audit.Service) Service {
+	return &service{
+		repo:     repo,
+		cfg:      cfg,
+		mailer:   mail,
+		keys:     keys,
+		oidc:     newOIDCClient(cfg.OIDC),
+		webauthn: newWebAuthn(cfg.WebAuthn),
+		hasher:   password.NewHasher(cfg.Password),
+		policy:   password.NewPolicy(cfg.Password),
+		audit:    auditService,
+	}
+}
+
+// ClientInfo describes the device a login comes from. It is recorded on the
+// session and used for per-IP throttling.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// TokenPair is returned on login and refresh.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // seconds until the access token expires
+}
+
+// LoginResult holds either an issued token pair or, when the user has 2FA
+// enabled, a challenge token that must be completed with a code.
+// PasswordChangeRequired is set when the password used no longer satisfies
+// the password policy, e.g. because it appears in the breached list.
+type LoginResult struct {
+	Tokens                 *TokenPair
+	User                   *User
+	ChallengeToken         string
+	ChallengeTTL           int64 // seconds
+	PasswordChangeRequired bool
+}
+
+// TOTPSetup is returned when enrolling an authenticator app.
+type TOTPSetup struct {
+	Secret          string
+	ProvisioningURI string
 }
 
-func (s *service) Login(email, password string) (string, *User, error) {
+func (s *service) Login(email, plainPassword string, client ClientInfo) (*LoginResult, error) {
+	keys := throttleKeys(email, client.IP)
+	if err := s.checkThrottle(keys); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.FindByEmail(email)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+	if user == nil {
+		return nil, s.recordLoginFailure(keys, ErrInvalidCredentials)
+	}
+
+	ok, err := s.hasher.Verify(user.PasswordHash, plainPassword)
+	if err != nil {
+		return nil, err
+	}
+	// A disabled account fails like a wrong password, so the answer does not
+	// reveal whether the password was right
+	if !ok || user.DisabledAt != nil {
+		return nil, s.recordLoginFailure(keys, ErrInvalidCredentials)
+	}
+
+	s.upgradePasswordHash(user, plainPassword)
+	changeRequired := s.passwordChangeRequired(plainPassword)
+
+	if user.TOTPEnabled {
+		result, err := s.createLoginChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		result.PasswordChangeRequired = changeRequired
+		return result, nil
+	}
+
+	if err := s.repo.DeleteLoginThrottleByKey(keys[0]); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens, User: user, PasswordChangeRequired: changeRequired}, nil
+}
+
+// upgradePasswordHash rehashes a verified password when the stored hash uses
+// an outdated algorithm or cost. Failures are logged and do not fail the login.
+func (s *service) upgradePasswordHash(user *User, plainPassword string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := s.hasher.Hash(plainPassword)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %s: %v", user.ID, err)
+		return
+	}
+	user.PasswordHash = hash
+	if err := s.repo.Update(user); err != nil {
+		log.Printf("Failed to store rehashed password for user %s: %v", user.ID, err)
+	}
+}
+
+// passwordChangeRequired reports whether a correct password breaks the
+// current policy. Users created before the policy keep logging in but are
+// asked to choose a new password.
+func (s *service) passwordChangeRequired(plainPassword string) bool {
+	err := s.policy.Validate(plainPassword)
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return true
+	}
+	if err != nil {
+		log.Printf("Failed to check password policy: %v", err)
+	}
+	return false
+}
+
+// validateNewPassword checks newPassword against the policy and the user's
+// recent passwords. HistorySize counts the current password.
+func (s *service) validateNewPassword(user *User, newPassword string) error {
+	if err := s.policy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	historySize := s.cfg.Password.HistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	hashes := []string{user.PasswordHash}
+	if historySize > 1 {
+		history, err := s.repo.FindPasswordHistory(user.ID, historySize-1)
+		if err != nil {
+			return err
+		}
+		for _, h := range history {
+			hashes = append(hashes, h.PasswordHash)
+		}
+	}
+
+	for _, hash := range hashes {
+		ok, err := s.hasher.Verify(hash, newPassword)
+		if err != nil && !errors.Is(err, password.ErrUnknownHash) {
+			return err
+		}
+		if ok {
+			return &password.PolicyError{Problems: []string{
+				fmt.Sprintf("password must not match any of your last %d passwords", historySize),
+			}}
+		}
+	}
+	return nil
+}
+
+// storePassword hashes newPassword for the user and keeps the old hash in the
+// password history. The password must already have been validated.
+func (s *service) storePassword(user *User, newPassword string) error {
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if keep := s.cfg.Password.HistorySize - 1; keep > 0 {
+		entry := &PasswordHistory{UserID: user.ID, PasswordHash: user.PasswordHash}
+		if err := s.repo.AddPasswordHistory(entry, keep); err != nil {
+			return err
+		}
+	}
+
+	user.PasswordHash = hash
+	return s.repo.Update(user)
+}
+
+func (s *service) createLoginChallenge(user *User) (*LoginResult, error) {
+	token, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(loginChallengeTTL),
+	}
+	if err := s.repo.CreateLoginChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{
+		User:           user,
+		ChallengeToken: token,
+		ChallengeTTL:   int64(loginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteTwoFactorLogin verifies a TOTP or recovery code against a pending
+// login challenge and issues tokens. A challenge is discarded after too many
+// wrong codes, forcing the password step to be repeated.
+func (s *service) CompleteTwoFactorLogin(challengeToken, code string, client ClientInfo) (*LoginResult, error) {
+	challenge, err := s.repo.FindLoginChallengeByHash(hashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= maxLoginChallengeAttempts {
+		return nil, ErrInvalidChallenge
+	}
+
+	user, err := s.repo.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.TOTPEnabled {
+		return nil, ErrInvalidChallenge
+	}
+
+	keys := throttleKeys(user.Email, client.IP)
+	if err := s.checkThrottle(keys); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifySecondFactor(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.repo.IncrementLoginChallengeAttempts(challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, s.recordLoginFailure(keys, ErrInvalidTOTPCode)
+	}
+
+	if err := s.repo.DeleteLoginChallenge(challenge.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteLoginThrottleByKey(keys[0]); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// pair is issued in the same session. Presenting an already-rotated token is
+// treated as theft and revokes the whole session.
+func (s *service) Refresh(refreshToken string) (*TokenPair, error) {
+	stored, err := s.repo.FindRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		if err := s.repo.RevokeSession(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.repo.FindSessionByID(stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.FindByID(stored.UserID)
+	if err != nil {
+		return nil, err
 	}
 	if user == nil {
-		return "", nil, errors.New("invalid credentials")
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newID := uuid.New()
+	revoked, err := s.repo.RevokeRefreshToken(stored.ID, &newID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// Another request rotated this token first
+		if err := s.repo.RevokeSession(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	now := time.Now()
+	if err := s.repo.ExtendSession(stored.FamilyID, now, now.Add(s.refreshTTL())); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, stored.FamilyID, newID)
+}
+
+// Logout ends the session the refresh token belongs to.
+func (s *service) Logout(refreshToken string) error {
+	stored, err := s.repo.FindRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return ErrInvalidRefreshToken
+	}
+	return s.repo.RevokeSession(stored.FamilyID)
+}
+
+// startSession records a new session for the login and issues its first tokens.
+func (s *service) startSession(user *User, client ClientInfo) (*TokenPair, error) {
+	if user.DisabledAt != nil {
+		return nil, ErrAccountDisabled
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL()),
+	}
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.ID, uuid.New())
+}
+
+// ValidateSession is called by the auth middleware for every access token. It
+// rejects tokens whose session was revoked or has expired, and records
+// activity at most once per sessionTouchInterval.
+func (s *service) ValidateSession(userID, sessionID uuid.UUID) error {
+	session, err := s.repo.FindSessionByID(sessionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if session == nil || session.UserID != userID || session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return ErrInvalidSession
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.repo.TouchSession(session.ID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) ListSessions(userID uuid.UUID) ([]Session, error) {
+	return s.repo.FindActiveSessions(userID)
+}
+
+// RevokeSession ends one of the user's own sessions.
+func (s *service) RevokeSession(actor audit.Actor, userID, sessionID uuid.UUID) error {
+	session, err := s.repo.FindSessionByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	if err := s.repo.RevokeSession(session.ID); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionRevoke, "session", session.ID.String(), nil, nil)
+	return nil
+}
+
+// RevokeAllSessions logs the user out everywhere, including the current session.
+func (s *service) RevokeAllSessions(actor audit.Actor, userID uuid.UUID) error {
+	if err := s.repo.RevokeUserSessions(userID, nil); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionRevoke, "session", "", nil, map[string]interface{}{"UserID": userID})
+	return nil
+}
+
+// RevokeEverySession logs every user out everywhere, e.g. after a suspected
+// compromise. API keys are not affected.
+func (s *service) RevokeEverySession(actor audit.Actor) error {
+	if err := s.repo.RevokeAllSessions(); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionRevoke, "session", "", nil, map[string]interface{}{"All": true})
+	return nil
+}
+
+func (s *service) refreshTTL() time.Duration {
+	return time.Hour * time.Duration(s.cfg.JWT.RefreshExpiration)
+}
+
+// throttleKeys returns the email key first, then the client IP key.
+func throttleKeys(email, clientIP string) []string {
+	keys := []string{"email:" + strings.ToLower(strings.TrimSpace(email))}
+	if clientIP != "" {
+		keys = append(keys, "ip:"+clientIP)
+	}
+	return keys
+}
+
+func (s *service) checkThrottle(keys []string) error {
+	throttles, err := s.repo.FindLoginThrottles(keys)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	locked := false
+	for _, t := range throttles {
+		if t.BlockedUntil == nil || !t.BlockedUntil.After(now) {
+			continue
+		}
+		if d := t.BlockedUntil.Sub(now); d > wait {
+			wait = d
+		}
+		locked = locked || t.Locked
+	}
+
+	if wait > 0 {
+		return &ThrottleError{RetryAfter: wait, Locked: locked}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against every key and blocks
+// further attempts according to the back-off policy. It returns cause,
+// wrapped in a ThrottleError when the next attempt has to wait.
+func (s *service) recordLoginFailure(keys []string, cause error) error {
+	windowStart := time.Now().Add(-time.Minute * time.Duration(s.cfg.Auth.LoginAttemptWindow))
+
+	var wait time.Duration
+	locked := false
+	for _, key := range keys {
+		throttle, err := s.repo.RecordLoginFailure(key, windowStart)
+		if err != nil {
+			return err
+		}
+
+		delay, lock := s.loginBackoff(throttle.Failures)
+		if delay <= 0 {
+			continue
+		}
+		if err := s.repo.BlockLoginThrottle(throttle.ID, time.Now().Add(delay), lock); err != nil {
+			return err
+		}
+		if delay > wait {
+			wait = delay
+		}
+		locked = locked || lock
+	}
+
+	if wait > 0 {
+		return &ThrottleError{RetryAfter: wait, Locked: locked, Err: cause}
+	}
+	return cause
+}
+
+// limitEmailRequests counts a request that sends email against the address
+// and the client IP, and refuses it while either has used up
+// EmailRequestLimit requests in the last EmailRequestWindow. Unknown
+// addresses count too, so the limit says nothing about which accounts exist.
+// The counters share the login throttle table under keys prefixed with kind.
+func (s *service) limitEmailRequests(kind, email string, client ClientInfo) error {
+	limit := s.cfg.Auth.EmailRequestLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	keys := throttleKeys(email, client.IP)
+	for i := range keys {
+		keys[i] = kind + ":" + keys[i]
+	}
+	var throttleErr *ThrottleError
+	if err := s.checkThrottle(keys); errors.As(err, &throttleErr) {
+		return &RateLimitError{RetryAfter: throttleErr.RetryAfter}
+	} else if err != nil {
+		return err
+	}
+
+	window := time.Minute * time.Duration(s.cfg.Auth.EmailRequestWindow)
+	now := time.Now()
+	for _, key := range keys {
+		throttle, err := s.repo.RecordLoginFailure(key, now.Add(-window))
+		if err != nil {
+			return err
+		}
+		if throttle.Failures >= limit {
+			if err := s.repo.BlockLoginThrottle(throttle.ID, now.Add(window), false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loginBackoff returns how long to refuse attempts after the given number of
+// consecutive failures. Below the threshold the delay doubles from the base
+// back-off; from the threshold on the account is locked, and every further
+// failure doubles the lockout.
+func (s *service) loginBackoff(failures int) (time.Duration, bool) {
+	maxAttempts := s.cfg.Auth.MaxLoginAttempts
+	if maxAttempts > 0 && failures >= maxAttempts {
+		base := time.Minute * time.Duration(s.cfg.Auth.LockoutDuration)
+		return doubled(base, failures-maxAttempts), true
+	}
+
+	base := time.Second * time.Duration(s.cfg.Auth.LoginBackoffBase)
+	return doubled(base, failures-1), false
+}
+
+// doubled returns base * 2^n, capped at maxLoginBlock.
+func doubled(base time.Duration, n int) time.Duration {
+	d := base
+	for i := 0; i < n && d < maxLoginBlock; i++ {
+		d *= 2
+	}
+	if d > maxLoginBlock {
+		return maxLoginBlock
+	}
+	return d
+}
+
+func (s *service) ListLoginThrottles() ([]LoginThrottle, error) {
+	return s.repo.ListLoginThrottles()
+}
+
+func (s *service) ClearLoginThrottle(actor audit.Actor, id uuid.UUID) error {
+	if err := s.repo.DeleteLoginThrottle(id); err != nil {
+		return err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", nil, errors.New("invalid credentials")
+	s.audit.Record(actor, audit.ActionDelete, "login_lockout", id.String(), nil, nil)
+	return nil
+}
+
+func (s *service) issueTokens(user *User, sessionID, refreshID uuid.UUID) (*TokenPair, error) {
+	expiration := time.Minute * time.Duration(s.cfg.JWT.Expiration)
+	accessToken, err := s.generateToken(user, sessionID, expiration)
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := s.generateToken(user)
+	refreshToken, err := generateRandomToken()
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	return token, user, nil
+	stored := &RefreshToken{
+		ID:        refreshID,
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL()),
+	}
+	if err := s.repo.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(expiration.Seconds()),
+	}, nil
 }
 
-func (s *service) generateToken(user *User) (string, error) {
+func (s *service) generateToken(user *User, sessionID uuid.UUID, expiration time.Duration) (string, error) {
 	claims := jwt.MapClaims{
-		"sub": user.ID.String(),
-		"exp": time.Now().Add(time.Hour * time.Duration(s.cfg.JWT.Expiration)).Unix(),
+		"sub":  user.ID.String(),
+		"sid":  sessionID.String(),
+		"jti":  uuid.New().String(),
+		"role": user.Role,
+		"exp":  time.Now().Add(expiration).Unix(),
+	}
+
+	return s.keys.Sign(claims)
+}
+
+// JWKS returns the public keys that verify access tokens.
+func (s *service) JWKS() keyring.JWKSet {
+	return s.keys.JWKS()
+}
+
+// generateRandomToken returns a URL-safe opaque token with 256 bits of entropy.
+func generateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.cfg.JWT.Secret))
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func (s *service) UpdateEmail(userID uuid.UUID, newEmail string) error {
+func (s *service) UpdateEmail(actor audit.Actor, userID uuid.UUID, newEmail string) error {
 	user, err := s.repo.FindByID(userID)
 	if err != nil {
 		return err
@@ -74,11 +787,19 @@ func (s *service) UpdateEmail(userID uuid.UUID, newEmail string) error {
 		return errors.New("email already in use")
 	}
 
+	before := user.auditState()
 	user.Email = newEmail
-	return s.repo.Update(user)
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "user", user.ID.String(), before, user.auditState())
+	return nil
 }
 
-func (s *service) UpdatePassword(userID uuid.UUID, newPassword string) error {
+// UpdatePassword changes the password and ends every other session of the
+// user; the session making the change stays logged in.
+func (s *service) UpdatePassword(actor audit.Actor, userID, currentSessionID uuid.UUID, newPassword string) error {
 	user, err := s.repo.FindByID(userID)
 	if err != nil {
 		return err
@@ -87,11 +808,669 @@ func (s *service) UpdatePassword(userID uuid.UUID, newPassword string) error {
 		return errors.New("user not found")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	if err != nil {
+	if err := s.validateNewPassword(user, newPassword); err != nil {
+		return err
+	}
+	if err := s.storePassword(user, newPassword); err != nil {
+		return err
+	}
+	if err := s.repo.RevokeUserSessions(user.ID, &currentSessionID); err != nil {
 		return err
 	}
 
-	user.PasswordHash = string(hashedPassword)
-	return s.repo.Update(user)
+	s.audit.Record(actor, audit.ActionUpdate, "password", user.ID.String(), nil, nil)
+	return nil
+}
+
+// SetupTOTP generates a new secret for the user. 2FA stays disabled until the
+// secret is confirmed with EnableTOTP.
+func (s *service) SetupTOTP(userID uuid.UUID) (*TOTPSetup, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.cfg.Auth.TOTPIssuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = key.Secret()
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+	}, nil
+}
+
+func (s *service) EnableTOTP(actor audit.Actor, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+
+	ok, err := s.validateTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionEnable, "two_factor", user.ID.String(), nil, nil)
+
+	return codes, nil
+}
+
+func (s *service) DisableTOTP(actor audit.Actor, userID uuid.UUID, code string) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	ok, err := s.verifySecondFactor(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTOTPCode
+	}
+
+	if err := s.repo.DeleteRecoveryCodes(user.ID); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionDisable, "two_factor", user.ID.String(), nil, nil)
+	return nil
+}
+
+func (s *service) RegenerateRecoveryCodes(actor audit.Actor, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	ok, err := s.validateTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionRegenerate, "recovery_codes", user.ID.String(), nil, nil)
+	return codes, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery
+// code. Recovery codes are consumed on success.
+func (s *service) verifySecondFactor(user *User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	ok, err := s.validateTOTP(user, code)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	recovery, err := s.repo.FindUnusedRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	if recovery == nil {
+		return false, nil
+	}
+	return s.repo.MarkRecoveryCodeUsed(recovery.ID)
+}
+
+// validateTOTP checks a code against the user's secret, allowing one time
+// step of clock drift either way. Each code is accepted once: a code whose
+// time step is not after the last accepted one is refused, so an observed
+// code cannot be replayed while it is still valid.
+func (s *service) validateTOTP(user *User, code string) (bool, error) {
+	now := time.Now()
+	for skew := -1; skew <= 1; skew++ {
+		at := now.Add(time.Duration(skew) * totpPeriod * time.Second)
+		ok, err := totp.ValidateCustom(code, user.TOTPSecret, at, totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil || !ok {
+			continue
+		}
+
+		step := at.Unix() / totpPeriod
+		if step <= user.TOTPLastStep {
+			return false, nil
+		}
+		used, err := s.repo.UseTOTPStep(user.ID, step)
+		if err != nil || !used {
+			return false, err
+		}
+		user.TOTPLastStep = step
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *service) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	stored := make([]RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		plain = append(plain, code)
+		stored = append(stored, RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashToken(normalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// generateRecoveryCode returns a code like "k3j9d-2mx7q".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return encoded[:5] + "-" + encoded[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
+func (s *service) ListUsers() ([]User, error) {
+	return s.repo.FindAll()
+}
+
+// InviteUser creates an account with the given role and a random temporary
+// password, which is returned once so it can be handed to the new user.
+func (s *service) InviteUser(actor audit.Actor, email, role string) (*User, string, error) {
+	tempPassword, err := generateTempPassword()
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.createUser(actor, email, role, tempPassword)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, tempPassword, nil
+}
+
+// CreateUser creates an account with a password chosen by the operator. The
+// password must satisfy the password policy.
+func (s *service) CreateUser(actor audit.Actor, email, role, plainPassword string) (*User, error) {
+	if err := s.policy.Validate(plainPassword); err != nil {
+		return nil, err
+	}
+	return s.createUser(actor, email, role, plainPassword)
+}
+
+func (s *service) createUser(actor audit.Actor, email, role, plainPassword string) (*User, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	existingUser, err := s.repo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, errors.New("email already in use")
+	}
+
+	hashedPassword, err := s.hasher.Hash(plainPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Email:        email,
+		PasswordHash: hashedPassword,
+		Role:         role,
+	}
+	if err := s.repo.Create(user); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionCreate, "user", user.ID.String(), nil, user.auditState())
+
+	return user, nil
+}
+
+// generateTempPassword returns a random password for accounts set up by an
+// admin. Logging in with it asks for a new password when it does not
+// satisfy the policy.
+func generateTempPassword() (string, error) {
+	token, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	return token[:20], nil
+}
+
+// SetUserPassword replaces another user's password, e.g. when they are locked
+// out, and ends all their sessions. With an empty newPassword a temporary
+// password is generated; it is returned so it can be handed over.
+func (s *service) SetUserPassword(actor audit.Actor, userID uuid.UUID, newPassword string) (string, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", errors.New("user not found")
+	}
+
+	if newPassword == "" {
+		if newPassword, err = generateTempPassword(); err != nil {
+			return "", err
+		}
+	} else if err := s.validateNewPassword(user, newPassword); err != nil {
+		return "", err
+	}
+
+	if err := s.storePassword(user, newPassword); err != nil {
+		return "", err
+	}
+	if err := s.repo.RevokeUserSessions(user.ID, nil); err != nil {
+		return "", err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "password", user.ID.String(), nil, nil)
+	return newPassword, nil
+}
+
+// DisableUser blocks every way of signing in, including API keys, and ends
+// the user's sessions. The account and its content are kept.
+func (s *service) DisableUser(actor audit.Actor, userID uuid.UUID) error {
+	return s.setUserDisabled(actor, userID, true)
+}
+
+func (s *service) EnableUser(actor audit.Actor, userID uuid.UUID) error {
+	return s.setUserDisabled(actor, userID, false)
+}
+
+func (s *service) setUserDisabled(actor audit.Actor, userID uuid.UUID, disabled bool) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if (user.DisabledAt != nil) == disabled {
+		return nil
+	}
+
+	before := user.auditState()
+	action := audit.ActionEnable
+	user.DisabledAt = nil
+	if disabled {
+		action = audit.ActionDisable
+		now := time.Now()
+		user.DisabledAt = &now
+	}
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+	if disabled {
+		if err := s.repo.RevokeUserSessions(user.ID, nil); err != nil {
+			return err
+		}
+	}
+
+	s.audit.Record(actor, action, "user", user.ID.String(), before, user.auditState())
+	return nil
+}
+
+func (s *service) UpdateUserRole(actor audit.Actor, userID uuid.UUID, role string) (*User, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if actor.UserID == userID {
+		return nil, errors.New("cannot change your own role")
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.Role == RoleOwner && role != RoleOwner {
+		if err := s.ensureAnotherOwner(); err != nil {
+			return nil, err
+		}
+	}
+
+	before := user.auditState()
+	user.Role = role
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "user", user.ID.String(), before, user.auditState())
+	return user, nil
+}
+
+func (s *service) DeleteUser(actor audit.Actor, userID uuid.UUID) error {
+	if actor.UserID == userID {
+		return errors.New("cannot delete your own account")
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if user.Role == RoleOwner {
+		if err := s.ensureAnotherOwner(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Delete(userID); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionDelete, "user", user.ID.String(), user.auditState(), nil)
+	return nil
+}
+
+// ensureAnotherOwner fails unless at least two owners exist, so that removing
+// one of them still leaves the site manageable.
+func (s *service) ensureAnotherOwner() error {
+	owners, err := s.repo.CountByRole(RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners < 2 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// RequestPasswordReset emails a single-use reset link. It succeeds silently
+// for unknown addresses so the endpoint cannot be used to discover accounts;
+// the link is created and sent in the background, so the answer takes as
+// long for them as for real accounts.
+func (s *service) RequestPasswordReset(email string, client ClientInfo) error {
+	if err := s.limitEmailRequests("reset", email, client); err != nil {
+		return err
+	}
+
+	go s.sendPasswordReset(email)
+	return nil
+}
+
+// sendPasswordReset stores and emails a reset link for the account of the
+// email, if there is an enabled one. Failures are only logged, as the
+// request has already been answered.
+func (s *service) sendPasswordReset(email string) {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		log.Printf("failed to create password reset token: %v", err)
+		return
+	}
+	if user == nil || user.DisabledAt != nil {
+		return
+	}
+
+	// Only the most recent link stays valid
+	if err := s.repo.InvalidatePasswordResetTokens(user.ID); err != nil {
+		log.Printf("failed to create password reset token: %v", err)
+		return
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		log.Printf("failed to create password reset token: %v", err)
+		return
+	}
+
+	ttl := time.Minute * time.Duration(s.cfg.Auth.PasswordResetTTL)
+	resetToken := &PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.repo.CreatePasswordResetToken(resetToken); err != nil {
+		log.Printf("failed to create password reset token: %v", err)
+		return
+	}
+
+	link := s.cfg.Auth.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("A password reset was requested for your account.\n\n"+
+			"Open the link below to choose a new password. It expires in %d minutes and can be used once.\n\n%s\n\n"+
+			"If you did not request this, you can ignore this email.\n", s.cfg.Auth.PasswordResetTTL, link),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("failed to send password reset email: %v", err)
+	}
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out everywhere by revoking their refresh tokens.
+func (s *service) ResetPassword(token, newPassword string) error {
+	resetToken, err := s.repo.FindPasswordResetTokenByHash(hashToken(token))
+	if err != nil {
+		return err
+	}
+	if resetToken == nil || resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.repo.FindByID(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+
+	// Validate first so that a rejected password does not use up the token
+	if err := s.validateNewPassword(user, newPassword); err != nil {
+		return err
+	}
+
+	used, err := s.repo.MarkPasswordResetTokenUsed(resetToken.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.storePassword(user, newPassword); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeUserSessions(user.ID, nil); err != nil {
+		return err
+	}
+	return s.repo.DeleteLoginThrottleByKey(throttleKeys(user.Email, "")[0])
+}
+
+// CreateAPIKey issues a key limited to scopes, which must all be held by the
+// creating user. The plain key is returned once and never stored.
+func (s *service) CreateAPIKey(actor audit.Actor, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil {
+		return nil, "", errors.New("user not found")
+	}
+
+	granted := PermissionsForRole(user.Role)
+	for _, scope := range scopes {
+		if !IsValidPermission(scope) {
+			return nil, "", fmt.Errorf("unknown scope: %s", scope)
+		}
+		if !containsString(granted, scope) {
+			return nil, "", fmt.Errorf("scope not allowed for your role: %s", scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.New("expires_at must be in the future")
+	}
+
+	secret, err := generateRandomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	plainKey := apiKeyPrefix + secret
+
+	key := &APIKey{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    plainKey[:len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(plainKey),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.CreateAPIKey(key); err != nil {
+		return nil, "", err
+	}
+
+	s.audit.Record(actor, audit.ActionCreate, "api_key", key.ID.String(), nil, key)
+
+	return key, plainKey, nil
+}
+
+func (s *service) ListAPIKeys() ([]APIKey, error) {
+	return s.repo.FindAllAPIKeys()
+}
+
+func (s *service) RevokeAPIKey(actor audit.Actor, id uuid.UUID) error {
+	revoked, err := s.repo.RevokeAPIKey(id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("api key not found or already revoked")
+	}
+
+	s.audit.Record(actor, audit.ActionRevoke, "api_key", id.String(), nil, nil)
+	return nil
+}
+
+// AuthenticateAPIKey validates a key and returns its effective permissions:
+// the key's scopes that its owner's current role still grants.
+func (s *service) AuthenticateAPIKey(plainKey string) (*APIKey, []string, error) {
+	key, err := s.repo.FindAPIKeyByHash(hashToken(plainKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if key == nil || key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.repo.FindByID(key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || user.DisabledAt != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	granted := PermissionsForRole(user.Role)
+	permissions := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if containsString(granted, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	// Avoid a write on every request from busy CI jobs
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.TouchAPIKey(key.ID, now); err != nil {
+			return nil, nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, permissions, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
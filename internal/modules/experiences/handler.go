@@ -1,11 +1,15 @@
 package experiences
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
 
@@ -98,7 +102,7 @@ func (h *Handler) CreateExperience(c *gin.Context) {
 
 	req.ProfileID = profile.ID
 
-	experience, err := h.service.Create(&req)
+	experience, err := h.service.Create(audit.ActorFromContext(c), &req)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create experience", err.Error())
 		return
@@ -133,7 +137,7 @@ func (h *Handler) UpdateExperience(c *gin.Context) {
 		return
 	}
 
-	experience, err := h.service.Update(id, &req)
+	experience, err := h.service.Update(audit.ActorFromContext(c), id, &req)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update experience", err.Error())
 		return
@@ -160,9 +164,46 @@ func (h *Handler) DeleteExperience(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(audit.ActorFromContext(c), id); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete experience", err.Error())
 		return
 	}
 	response.Success(c, http.StatusOK, "Experience deleted successfully", nil)
 }
+
+// RestoreExperienceRevision godoc
+// @Summary      Admin - Restore Experience Revision
+// @Description  Put the fields saved in a revision back into the experience. The result is stored as a new revision; history is never rewritten.
+// @Tags         Admin - Experiences
+// @Produce      json
+// @Param        id      path     string  true  "Experience ID"
+// @Param        number  path     int     true  "Revision number"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/experiences/{id}/revisions/{number}/restore [post]
+func (h *Handler) RestoreExperienceRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid revision number", "revision number must be a positive integer")
+		return
+	}
+
+	experience, err := h.service.RestoreRevision(audit.ActorFromContext(c), id, number)
+	if err != nil {
+		if errors.Is(err, ErrExperienceNotFound) || errors.Is(err, revisions.ErrRevisionNotFound) {
+			response.Error(c, http.StatusNotFound, "Failed to restore experience", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to restore experience", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Experience restored successfully", experience)
+}
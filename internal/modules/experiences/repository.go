@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
 	"gorm.io/gorm"
 )
 
@@ -25,15 +26,24 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r *repository) Create(experience *Experience) error {
-	return r.db.Create(experience).Error
+	if err := r.db.Create(experience).Error; err != nil {
+		return err
+	}
+	return search.Index(r.db, "experiences", experience.ID)
 }
 
 func (r *repository) Update(experience *Experience) error {
-	return r.db.Save(experience).Error
+	if err := r.db.Save(experience).Error; err != nil {
+		return err
+	}
+	return search.Index(r.db, "experiences", experience.ID)
 }
 
 func (r *repository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&Experience{}, "id = ?", id).Error
+	if err := r.db.Delete(&Experience{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	return search.Unindex(r.db, "experiences", id)
 }
 
 func (r *repository) FindByID(id uuid.UUID) (*Experience, error) {
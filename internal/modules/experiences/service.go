@@ -5,22 +5,54 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
 )
 
 type Service interface {
-	Create(req *CreateExperienceRequest) (*Experience, error)
-	Update(id uuid.UUID, req *UpdateExperienceRequest) (*Experience, error)
-	Delete(id uuid.UUID) error
+	Create(actor audit.Actor, req *CreateExperienceRequest) (*Experience, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdateExperienceRequest) (*Experience, error)
+	Delete(actor audit.Actor, id uuid.UUID) error
 	GetByID(id uuid.UUID) (*Experience, error)
 	GetAll() ([]Experience, error)
+	RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Experience, error)
 }
 
+var ErrExperienceNotFound = errors.New("experience not found")
+
 type service struct {
-	repo Repository
+	repo      Repository
+	audit     audit.Service
+	revisions revisions.Service
+}
+
+func NewService(repo Repository, auditService audit.Service, revisionService revisions.Service) Service {
+	return &service{repo: repo, audit: auditService, revisions: revisionService}
+}
+
+// experienceRevision is the part of an experience kept in its revisions and
+// put back when one is restored.
+type experienceRevision struct {
+	Company     string `json:"company"`
+	Position    string `json:"position"`
+	Description string `json:"description"`
+	StartDate   string `json:"start_date"` // YYYY-MM-DD
+	EndDate     string `json:"end_date"`   // YYYY-MM-DD
+	IsCurrent   bool   `json:"is_current"`
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func (e *Experience) revisionState() experienceRevision {
+	state := experienceRevision{
+		Company:     e.Company,
+		Position:    e.Position,
+		Description: e.Description,
+		StartDate:   e.StartDate.Format("2006-01-02"),
+		IsCurrent:   e.IsCurrent,
+	}
+	if e.EndDate != nil {
+		state.EndDate = e.EndDate.Format("2006-01-02")
+	}
+	return state
 }
 
 type CreateExperienceRequest struct {
@@ -53,7 +85,7 @@ func parseDate(dateStr string) *time.Time {
 	return &t
 }
 
-func (s *service) Create(req *CreateExperienceRequest) (*Experience, error) {
+func (s *service) Create(actor audit.Actor, req *CreateExperienceRequest) (*Experience, error) {
 	startDate := parseDate(req.StartDate)
 	if startDate == nil {
 		return nil, errors.New("invalid start_date format")
@@ -73,17 +105,21 @@ func (s *service) Create(req *CreateExperienceRequest) (*Experience, error) {
 		return nil, err
 	}
 
+	s.audit.Record(actor, audit.ActionCreate, "experience", experience.ID.String(), nil, experience)
+	s.revisions.Record(actor, revisions.EntityExperience, experience.ID, nil, experience.revisionState())
+
 	return experience, nil
 }
 
-func (s *service) Update(id uuid.UUID, req *UpdateExperienceRequest) (*Experience, error) {
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdateExperienceRequest) (*Experience, error) {
 	experience, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if experience == nil {
-		return nil, errors.New("experience not found")
+		return nil, ErrExperienceNotFound
 	}
+	before := *experience
 
 	experience.Company = req.Company
 	experience.Position = req.Position
@@ -102,11 +138,27 @@ func (s *service) Update(id uuid.UUID, req *UpdateExperienceRequest) (*Experienc
 		return nil, err
 	}
 
+	s.audit.Record(actor, audit.ActionUpdate, "experience", experience.ID.String(), before, experience)
+	s.revisions.Record(actor, revisions.EntityExperience, experience.ID, before.revisionState(), experience.revisionState())
+
 	return experience, nil
 }
 
-func (s *service) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *service) Delete(actor audit.Actor, id uuid.UUID) error {
+	experience, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	if experience != nil {
+		s.audit.Record(actor, audit.ActionDelete, "experience", experience.ID.String(), experience, nil)
+	}
+	s.revisions.DeleteDrafts(revisions.EntityExperience, id)
+	return nil
 }
 
 func (s *service) GetByID(id uuid.UUID) (*Experience, error) {
@@ -116,3 +168,43 @@ func (s *service) GetByID(id uuid.UUID) (*Experience, error) {
 func (s *service) GetAll() ([]Experience, error) {
 	return s.repo.FindAll(0, 0)
 }
+
+// RestoreRevision puts the fields saved in a revision back into the
+// experience and records the result as a new revision.
+func (s *service) RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Experience, error) {
+	experience, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if experience == nil {
+		return nil, ErrExperienceNotFound
+	}
+
+	revision, err := s.revisions.Get(revisions.EntityExperience, id, number)
+	if err != nil {
+		return nil, err
+	}
+	var state experienceRevision
+	if err := revisions.Decode(revision, &state); err != nil {
+		return nil, err
+	}
+	before := *experience
+
+	experience.Company = state.Company
+	experience.Position = state.Position
+	experience.Description = state.Description
+	if startDate := parseDate(state.StartDate); startDate != nil {
+		experience.StartDate = *startDate
+	}
+	experience.EndDate = parseDate(state.EndDate)
+	experience.IsCurrent = state.IsCurrent
+
+	if err := s.repo.Update(experience); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionRestore, "experience", experience.ID.String(), before, experience)
+	s.revisions.RecordRestore(actor, revisions.EntityExperience, experience.ID, experience.revisionState(), revision.Number)
+
+	return experience, nil
+}
@@ -0,0 +1,158 @@
+package feeds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+// format is one of the feed formats, with its renderer.
+type format struct {
+	name        string
+	contentType string
+	render      func(feed *Feed, selfURL string) ([]byte, error)
+}
+
+var (
+	formatRSS  = format{"rss", "application/rss+xml; charset=utf-8", renderRSS}
+	formatAtom = format{"atom", "application/atom+xml; charset=utf-8", renderAtom}
+	formatJSON = format{"json", "application/feed+json; charset=utf-8", renderJSONFeed}
+)
+
+type Handler struct {
+	service     Service
+	baseURL     string
+	fullContent bool // default when ?content= is not given
+}
+
+func NewHandler(service Service, baseURL string, fullContent bool) *Handler {
+	return &Handler{
+		service:     service,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		fullContent: fullContent,
+	}
+}
+
+// RSS serves /feed.xml and /tags/:slug/feed.xml as RSS 2.0.
+func (h *Handler) RSS(c *gin.Context) {
+	h.serve(c, formatRSS)
+}
+
+// Atom serves /atom.xml and /tags/:slug/atom.xml.
+func (h *Handler) Atom(c *gin.Context) {
+	h.serve(c, formatAtom)
+}
+
+// JSON serves /feed.json and /tags/:slug/feed.json as JSON Feed 1.1.
+func (h *Handler) JSON(c *gin.Context) {
+	h.serve(c, formatJSON)
+}
+
+// serve renders a feed of the latest published posts, of one tag when the
+// route has a slug. ?content=full or ?content=summary overrides the
+// configured mode. Readers poll feeds, so the posts are only loaded and
+// rendered when If-None-Match shows the reader's copy is out of date.
+func (h *Handler) serve(c *gin.Context, f format) {
+	fullContent := h.fullContent
+	switch c.Query("content") {
+	case "":
+	case "full":
+		fullContent = true
+	case "summary":
+		fullContent = false
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid content mode", `content must be "full" or "summary"`)
+		return
+	}
+
+	tagSlug := c.Param("slug")
+	lastModified, count, err := h.service.Version(tagSlug)
+	if err != nil {
+		if errors.Is(err, posts.ErrTagNotFound) {
+			h.redirectTag(c, tagSlug)
+			return
+		}
+		writeError(c, err)
+		return
+	}
+
+	etag := feedETag(f.name, tagSlug, fullContent, lastModified, count)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c.Request, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	feed, err := h.service.Build(tagSlug, fullContent)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	body, err := f.render(feed, h.baseURL+c.Request.URL.RequestURI())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to render feed", err.Error())
+		return
+	}
+	c.Data(http.StatusOK, f.contentType, body)
+}
+
+// redirectTag sends readers of the feed of a renamed tag to its current
+// feed, so they update their subscription; an unknown tag is not found.
+func (h *Handler) redirectTag(c *gin.Context, oldSlug string) {
+	current, err := h.service.ResolveTag(oldSlug)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if current == "" {
+		writeError(c, posts.ErrTagNotFound)
+		return
+	}
+
+	location := strings.Replace(c.FullPath(), ":slug", url.PathEscape(current), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
+
+func writeError(c *gin.Context, err error) {
+	if errors.Is(err, posts.ErrTagNotFound) {
+		response.Error(c, http.StatusNotFound, "Tag not found", err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, "Failed to fetch feed", err.Error())
+}
+
+// feedETag identifies a rendering of a feed. It changes when a post in it is
+// edited, added or removed, and differs between formats and content modes.
+func feedETag(format, tagSlug string, fullContent bool, lastModified time.Time, count int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%t|%d|%d", format, tagSlug, fullContent, lastModified.UnixNano(), count)))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// notModified applies If-None-Match. If-Modified-Since is not evaluated: a
+// post deleted or moved out of the feed can leave the latest change time
+// where it was, which only the ETag, covering the post count too, reflects.
+func notModified(r *http.Request, etag string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate != "" && (candidate == etag || candidate == "*") {
+			return true
+		}
+	}
+	return false
+}
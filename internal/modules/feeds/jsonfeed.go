@@ -0,0 +1,82 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Authors     []jsonAuthor   `json:"authors,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html,omitempty"`
+	ContentText   string   `json:"content_text,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// renderJSONFeed writes the feed as JSON Feed 1.1. Every item needs content,
+// so in summary mode the summary is given as content_text.
+func renderJSONFeed(feed *Feed, selfURL string) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.SiteURL,
+		FeedURL:     selfURL,
+		Description: feed.Description,
+		Items:       make([]jsonFeedItem, 0, len(feed.Items)),
+	}
+	if feed.Author != "" {
+		doc.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+
+	for _, item := range feed.Items {
+		entry := jsonFeedItem{
+			ID:            "urn:uuid:" + item.ID.String(),
+			URL:           item.URL,
+			Title:         item.Title,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if item.Content != "" {
+			entry.ContentHTML = item.Content
+		} else {
+			entry.ContentText = item.Summary
+		}
+		if item.Image != nil {
+			entry.Image = item.Image.URL
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	// HTML is left unescaped, as content_html is read by feed readers, not
+	// embedded in a page
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
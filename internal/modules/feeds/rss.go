@@ -0,0 +1,102 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Content     *rssCDATA     `xml:"content:encoded,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// renderRSS writes the feed as RSS 2.0. Full content goes into
+// content:encoded, leaving description for the summary. GUIDs are the post
+// IDs, so renaming a post does not make it show up as new.
+func renderRSS(feed *Feed, selfURL string) ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        feed.SiteURL,
+		Description: feed.Description,
+		Self:        rssLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if !feed.Updated.IsZero() {
+		channel.LastBuildDate = feed.Updated.UTC().Format(http.TimeFormat)
+	}
+	if channel.Description == "" {
+		channel.Description = feed.Title
+	}
+
+	for _, item := range feed.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{IsPermaLink: "false", Value: "urn:uuid:" + item.ID.String()},
+			PubDate:     item.Published.UTC().Format(http.TimeFormat),
+			Description: item.Summary,
+			Categories:  item.Tags,
+		}
+		if item.Content != "" {
+			entry.Content = &rssCDATA{Value: item.Content}
+		}
+		if item.Image != nil {
+			entry.Enclosure = &rssEnclosure{URL: item.Image.URL, Length: item.Image.Size, Type: item.Image.MimeType}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	body, err := xml.MarshalIndent(rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Channel: channel,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
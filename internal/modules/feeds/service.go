@@ -0,0 +1,194 @@
+package feeds
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/tags"
+)
+
+// Feed is the format-independent content of a blog feed.
+type Feed struct {
+	Title       string
+	Description string
+	SiteURL     string
+	Author      string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is one post. Content is empty in summary mode.
+type Item struct {
+	ID        uuid.UUID
+	Title     string
+	URL       string
+	Summary   string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+	Tags      []string
+	Image     *Image
+}
+
+// Image is the primary image of a post, used as RSS enclosure and JSON
+// Feed image.
+type Image struct {
+	URL      string
+	MimeType string
+	Size     int64
+}
+
+type Service interface {
+	Version(tagSlug string) (time.Time, int64, error)
+	Build(tagSlug string, fullContent bool) (*Feed, error)
+	ResolveTag(oldSlug string) (string, error)
+}
+
+type service struct {
+	posts   posts.Service
+	tags    tags.Service
+	site    config.SiteConfig
+	limit   int
+	baseURL string
+}
+
+func NewService(postService posts.Service, tagService tags.Service, cfg *config.Config) Service {
+	return &service{
+		posts:   postService,
+		tags:    tagService,
+		site:    cfg.Site,
+		limit:   cfg.Feed.Limit,
+		baseURL: strings.TrimRight(cfg.Server.BaseURL, "/"),
+	}
+}
+
+// Version returns when the posts of a feed last changed and how many there
+// are; a feed is unchanged as long as both are.
+func (s *service) Version(tagSlug string) (time.Time, int64, error) {
+	return s.posts.GetPublishedVersion(tagSlug)
+}
+
+// ResolveTag returns the current slug of the tag that used to be at
+// oldSlug, or an empty string when there is none.
+func (s *service) ResolveTag(oldSlug string) (string, error) {
+	return s.tags.ResolveSlug(oldSlug)
+}
+
+func (s *service) Build(tagSlug string, fullContent bool) (*Feed, error) {
+	published, err := s.posts.GetPublished(tagSlug, s.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	siteURL := strings.TrimRight(s.site.URL, "/")
+	feed := &Feed{
+		Title:       s.site.Title,
+		Description: s.site.Description,
+		SiteURL:     siteURL,
+		Author:      s.site.Author,
+		Items:       make([]Item, 0, len(published)),
+	}
+	if tagSlug != "" {
+		feed.Title += ": " + tagName(published, tagSlug)
+	}
+
+	for i := range published {
+		post := &published[i]
+		item := Item{
+			ID:        post.ID,
+			Title:     post.Title,
+			URL:       s.site.PostURL(post.Slug),
+			Summary:   post.Summary,
+			Published: publishedAt(post),
+			Updated:   post.UpdatedAt,
+			Image:     s.primaryImage(post),
+		}
+		if fullContent {
+			item.Content = s.absoluteURLs(post.ContentHTML, item.URL)
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		feed.Items = append(feed.Items, item)
+
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+	}
+	return feed, nil
+}
+
+func publishedAt(post *posts.Post) time.Time {
+	switch {
+	case post.PublishedAt != nil:
+		return *post.PublishedAt
+	case post.PublishAt != nil:
+		return *post.PublishAt
+	default:
+		return post.CreatedAt
+	}
+}
+
+// tagName finds the display name of the tag in the posts of its feed.
+func tagName(published []posts.Post, tagSlug string) string {
+	for _, post := range published {
+		for _, tag := range post.Tags {
+			if tag.Slug == tagSlug {
+				return tag.Name
+			}
+		}
+	}
+	return tagSlug
+}
+
+// primaryImage returns the image marked primary, or else the first one.
+func (s *service) primaryImage(post *posts.Post) *Image {
+	if len(post.Images) == 0 {
+		return nil
+	}
+	image := post.Images[0]
+	for _, candidate := range post.Images {
+		if candidate.IsPrimary {
+			image = candidate
+			break
+		}
+	}
+	return &Image{
+		URL:      absolute(s.baseURL, image.FilePath),
+		MimeType: image.MimeType,
+		Size:     image.Size,
+	}
+}
+
+func absolute(base, path string) string {
+	if strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") {
+		return base + path
+	}
+	return path
+}
+
+var urlAttrPattern = regexp.MustCompile(`\b(src|href)="([^"]*)"`)
+
+// absoluteURLs rewrites root-relative URLs in post HTML, since feed readers
+// show it outside the site: images are served by this API, links point to
+// the frontend and heading anchors to the post page.
+func (s *service) absoluteURLs(html, itemURL string) string {
+	siteURL := strings.TrimRight(s.site.URL, "/")
+	return urlAttrPattern.ReplaceAllStringFunc(html, func(attr string) string {
+		match := urlAttrPattern.FindStringSubmatch(attr)
+		name, value := match[1], match[2]
+		switch {
+		case name == "src":
+			value = absolute(s.baseURL, value)
+		case strings.HasPrefix(value, "#"):
+			value = itemURL + value
+		default:
+			value = absolute(siteURL, value)
+		}
+		return name + `="` + value + `"`
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
 
@@ -40,7 +41,7 @@ func (h *Handler) Upload(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.UploadFile(file)
+	result, err := h.service.UploadFile(audit.ActorFromContext(c), file)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to upload image", err.Error())
 		return
@@ -68,7 +69,7 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteImage(id); err != nil {
+	if err := h.service.DeleteImage(audit.ActorFromContext(c), id); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete image", err.Error())
 		return
 	}
@@ -11,28 +11,31 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 )
 
 type Service interface {
-	UploadFile(file *multipart.FileHeader) (*ImageUploadResult, error)
-	DeleteImage(id uuid.UUID) error
+	UploadFile(actor audit.Actor, file *multipart.FileHeader) (*ImageUploadResult, error)
+	DeleteImage(actor audit.Actor, id uuid.UUID) error
 }
 
 type service struct {
 	repo    Repository
 	storage string
+	audit   audit.Service
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, auditService audit.Service) Service {
 	// Base storage path: relative to execution or absolute
 	// Using "storage" folder in current working directory for simplicity
 	return &service{
 		repo:    repo,
 		storage: "storage",
+		audit:   auditService,
 	}
 }
 
-func (s *service) UploadFile(file *multipart.FileHeader) (*ImageUploadResult, error) {
+func (s *service) UploadFile(actor audit.Actor, file *multipart.FileHeader) (*ImageUploadResult, error) {
 	// Validate file extension
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".webp" {
@@ -81,15 +84,19 @@ func (s *service) UploadFile(file *multipart.FileHeader) (*ImageUploadResult, er
 		fullURL = baseURL + publicPath
 	}
 
-	return &ImageUploadResult{
+	result := &ImageUploadResult{
 		FileName: newFilename,
 		FilePath: fullURL,
 		MimeType: file.Header.Get("Content-Type"),
 		Size:     file.Size,
-	}, nil
+	}
+
+	s.audit.Record(actor, audit.ActionUpload, "image", newFilename, nil, result)
+
+	return result, nil
 }
 
-func (s *service) DeleteImage(id uuid.UUID) error {
+func (s *service) DeleteImage(actor audit.Actor, id uuid.UUID) error {
 	image, err := s.repo.FindByID(id)
 	if err != nil {
 		return err
@@ -114,5 +121,10 @@ func (s *service) DeleteImage(id uuid.UUID) error {
 		fmt.Printf("failed to delete file: %v\n", err)
 	}
 
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionDelete, "image", image.ID.String(), image, nil)
+	return nil
 }
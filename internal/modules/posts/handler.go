@@ -1,10 +1,17 @@
 package posts
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
@@ -19,7 +26,7 @@ func NewHandler(service Service) *Handler {
 
 // GetPublicPosts godoc
 // @Summary      Public - Get All Posts
-// @Description  Retrieve a list of all published posts
+// @Description  Retrieve a list of all published posts, respecting scheduled publication and withdrawal. Posts in a series carry their part number and the previous and next parts in Series.
 // @Tags         Public - Posts
 // @Produce      json
 // @Success      200  {array}   Post
@@ -55,6 +62,17 @@ func (h *Handler) GetAdminPosts(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Posts fetched successfully", posts)
 }
 
+// GetPublicPostBySlug godoc
+// @Summary      Public - Get Post by Slug
+// @Description  Retrieve a published post by its slug. A post in a series carries "part N of M" and the previous and next parts in Series. A slug the post had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.
+// @Tags         Public - Posts
+// @Produce      json
+// @Param        slug  path      string  true  "Post slug"
+// @Success      200   {object}  Post
+// @Success      301   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /public/posts/{slug} [get]
 func (h *Handler) GetPublicPostBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	post, err := h.service.GetBySlug(slug)
@@ -62,11 +80,17 @@ func (h *Handler) GetPublicPostBySlug(c *gin.Context) {
 		response.Error(c, http.StatusInternalServerError, "Failed to fetch post", err.Error())
 		return
 	}
-	if post == nil {
-		response.Error(c, http.StatusNotFound, "Post not found", "post not found")
-		return
-	}
-	if !post.IsPublished {
+	if post == nil || !post.IsVisible(time.Now()) {
+		current, err := h.service.ResolveSlug(slug)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch post", err.Error())
+			return
+		}
+		if current != "" {
+			c.Header("Location", "/api/public/posts/"+url.PathEscape(current))
+			response.Success(c, http.StatusMovedPermanently, "Post moved", gin.H{"slug": current})
+			return
+		}
 		response.Error(c, http.StatusNotFound, "Post not found", "post not found")
 		return
 	}
@@ -75,7 +99,7 @@ func (h *Handler) GetPublicPostBySlug(c *gin.Context) {
 
 // CreatePost godoc
 // @Summary      Admin - Create Post
-// @Description  Create a new post
+// @Description  Create a new post. Set publish_at to publish it later and unpublish_at to withdraw it automatically. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken.
 // @Tags         Admin - Posts
 // @Accept       json
 // @Produce      json
@@ -83,6 +107,7 @@ func (h *Handler) GetPublicPostBySlug(c *gin.Context) {
 // @Security     BearerAuth
 // @Success      201  {object}  Post
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/posts [post]
 func (h *Handler) CreatePost(c *gin.Context) {
@@ -92,8 +117,20 @@ func (h *Handler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.Create(&req)
+	post, err := h.service.Create(audit.ActorFromContext(c), &req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidSchedule) {
+			response.Error(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrInvalidSlug) || errors.Is(err, slugs.ErrReservedSlug) {
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrSlugTaken) {
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to create post", err.Error())
 		return
 	}
@@ -111,6 +148,7 @@ func (h *Handler) CreatePost(c *gin.Context) {
 // @Security     BearerAuth
 // @Success      200  {object}  Post
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/posts/{id} [put]
 func (h *Handler) UpdatePost(c *gin.Context) {
@@ -127,8 +165,20 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.Update(id, &req)
+	post, err := h.service.Update(audit.ActorFromContext(c), id, &req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidSchedule) {
+			response.Error(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrInvalidSlug) || errors.Is(err, slugs.ErrReservedSlug) {
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrSlugTaken) {
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to update post", err.Error())
 		return
 	}
@@ -154,9 +204,46 @@ func (h *Handler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(audit.ActorFromContext(c), id); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete post", err.Error())
 		return
 	}
 	response.Success(c, http.StatusOK, "Post deleted successfully", nil)
 }
+
+// RestorePostRevision godoc
+// @Summary      Admin - Restore Post Revision
+// @Description  Put the fields saved in a revision back into the post. The result is stored as a new revision; history is never rewritten.
+// @Tags         Admin - Posts
+// @Produce      json
+// @Param        id      path     string  true  "Post ID"
+// @Param        number  path     int     true  "Revision number"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/posts/{id}/revisions/{number}/restore [post]
+func (h *Handler) RestorePostRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid revision number", "revision number must be a positive integer")
+		return
+	}
+
+	post, err := h.service.RestoreRevision(audit.ActorFromContext(c), id, number)
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) || errors.Is(err, revisions.ErrRevisionNotFound) {
+			response.Error(c, http.StatusNotFound, "Failed to restore post", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to restore post", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Post restored successfully", post)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/markdown"
 	"github.com/prakoso-id/personal-backend/internal/modules/images"
 )
 
@@ -11,26 +12,76 @@ type Post struct {
 	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	Title           string          `gorm:"type:varchar(255);not null"`
 	Slug            string          `gorm:"type:varchar(255);unique;not null"`
+	SlugPinned      bool            `gorm:"default:false"` // custom slug, kept when the title changes
 	ContentMarkdown string          `gorm:"type:text"`
+	ContentHTML     string          `gorm:"type:text"` // rendered from ContentMarkdown on save
+	TableOfContents markdown.TOC    `gorm:"type:jsonb;serializer:json"`
+	WordCount       int             `gorm:"default:0"`
+	ReadingTime     int             `gorm:"default:0"` // minutes
 	Summary         string          `gorm:"type:text"`
 	IsPublished     bool            `gorm:"default:false"`
 	PublishedAt     *time.Time
+	PublishAt       *time.Time      `gorm:"index"` // scheduled publication, cleared once done
+	UnpublishAt     *time.Time      `gorm:"index"` // scheduled withdrawal, cleared once done
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	Tags            []*Tag          `gorm:"many2many:post_tags;"`
 	Images          []images.Image  `gorm:"polymorphic:Entity;polymorphicValue:post"`
+	Series          *SeriesPart     `gorm:"-"` // set on public responses for posts in a series
+}
+
+// SeriesPart places a post in its series: part Part of Total, counting the
+// visible posts only, with links to the parts before and after it.
+type SeriesPart struct {
+	ID       uuid.UUID
+	Title    string
+	Slug     string
+	Part     int
+	Total    int
+	Previous *PartLink
+	Next     *PartLink
+}
+
+// PartLink points at another part of a series.
+type PartLink struct {
+	Title string
+	Slug  string
 }
 
 type Tag struct {
-	ID   uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name string    `gorm:"type:varchar(100);unique;not null"`
-	Slug string    `gorm:"type:varchar(100);unique;not null"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string    `gorm:"type:varchar(100);unique;not null"`
+	Slug        string    `gorm:"type:varchar(100);unique;not null"`
+	Description string    `gorm:"type:text"`
 }
 
 func (Post) TableName() string {
 	return "posts"
 }
 
+// IsVisible reports whether the post is public at now. A post whose
+// PublishAt has passed is visible even before the scheduler flips it.
+func (p *Post) IsVisible(now time.Time) bool {
+	if p.UnpublishAt != nil && !p.UnpublishAt.After(now) {
+		return false
+	}
+	return p.IsPublished || (p.PublishAt != nil && !p.PublishAt.After(now))
+}
+
+// Render caches the HTML, table of contents and reading statistics of the
+// post's markdown.
+func (p *Post) Render() error {
+	result, err := markdown.Render(p.ContentMarkdown)
+	if err != nil {
+		return err
+	}
+	p.ContentHTML = result.HTML
+	p.TableOfContents = result.TOC
+	p.WordCount = result.WordCount
+	p.ReadingTime = result.ReadingTime
+	return nil
+}
+
 func (Tag) TableName() string {
 	return "tags"
 }
@@ -2,9 +2,13 @@ package posts
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -12,10 +16,17 @@ type Repository interface {
 	Update(post *Post) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*Post, error)
-	FindBySlug(slug string) (*Post, error)
+	FindBySlug(slug string, publishedOnly bool) (*Post, error)
 	FindAll(publishedOnly bool, limit, offset int) ([]Post, error)
 	Count(publishedOnly bool) (int64, error)
 	FindOrCreateTag(name, slug string) (*Tag, error)
+	FindScheduled() ([]Post, error)
+	PublishDue(now time.Time) ([]Post, error)
+	UnpublishDue(now time.Time) ([]Post, error)
+	FindTagBySlug(slug string) (*Tag, error)
+	FindPublished(tagSlug string, limit int) ([]Post, error)
+	PublishedVersion(tagSlug string) (time.Time, int64, error)
+	FindSeriesParts(postIDs []uuid.UUID) (map[uuid.UUID]*SeriesPart, error)
 }
 
 type repository struct {
@@ -43,7 +54,10 @@ func (r *repository) FindOrCreateTag(name, tagSlug string) (*Tag, error) {
 }
 
 func (r *repository) Create(post *Post) error {
-	return r.db.Create(post).Error
+	if err := r.db.Create(post).Error; err != nil {
+		return slugConflict(err)
+	}
+	return search.Index(r.db, "posts", post.ID)
 }
 
 func (r *repository) Update(post *Post) error {
@@ -51,11 +65,26 @@ func (r *repository) Update(post *Post) error {
 	if err := r.db.Model(post).Association("Tags").Replace(post.Tags); err != nil {
 		return err
 	}
-	return r.db.Save(post).Error
+	if err := r.db.Save(post).Error; err != nil {
+		return slugConflict(err)
+	}
+	return search.Index(r.db, "posts", post.ID)
+}
+
+// slugConflict reports a violated unique slug, the only unique column
+// written here, as taken: another post got the slug after it was checked.
+func slugConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return slugs.ErrSlugTaken
+	}
+	return err
 }
 
 func (r *repository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&Post{}, "id = ?", id).Error
+	if err := r.db.Delete(&Post{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	return search.Unindex(r.db, "posts", id)
 }
 
 func (r *repository) FindByID(id uuid.UUID) (*Post, error) {
@@ -70,9 +99,19 @@ func (r *repository) FindByID(id uuid.UUID) (*Post, error) {
 	return &post, nil
 }
 
-func (r *repository) FindBySlug(slug string) (*Post, error) {
+// visible limits a query to posts that are public at now, including those
+// whose scheduled publication is due but not yet applied.
+func visible(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("(is_published = ? OR publish_at <= ?) AND (unpublish_at IS NULL OR unpublish_at > ?)", true, now, now)
+}
+
+func (r *repository) FindBySlug(slug string, publishedOnly bool) (*Post, error) {
 	var post Post
-	err := r.db.Preload("Tags").Preload("Images").First(&post, "slug = ?", slug).Error
+	query := r.db.Preload("Tags").Preload("Images")
+	if publishedOnly {
+		query = visible(query, time.Now())
+	}
+	err := query.First(&post, "slug = ?", slug).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -86,7 +125,7 @@ func (r *repository) FindAll(publishedOnly bool, limit, offset int) ([]Post, err
 	var posts []Post
 	query := r.db.Preload("Tags").Preload("Images").Order("created_at DESC")
 	if publishedOnly {
-		query = query.Where("is_published = ?", true)
+		query = visible(query, time.Now())
 	}
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
@@ -99,8 +138,197 @@ func (r *repository) Count(publishedOnly bool) (int64, error) {
 	var count int64
 	query := r.db.Model(&Post{})
 	if publishedOnly {
-		query = query.Where("is_published = ?", true)
+		query = visible(query, time.Now())
 	}
 	err := query.Count(&count).Error
 	return count, err
 }
+
+// FindScheduled returns posts with a pending publication or withdrawal.
+func (r *repository) FindScheduled() ([]Post, error) {
+	var posts []Post
+	err := r.db.Where("publish_at IS NOT NULL OR unpublish_at IS NOT NULL").Find(&posts).Error
+	return posts, err
+}
+
+// PublishDue publishes posts whose PublishAt has passed and returns them.
+// Posts that are also due for withdrawal are left to UnpublishDue.
+func (r *repository) PublishDue(now time.Time) ([]Post, error) {
+	var posts []Post
+	err := r.db.Model(&posts).Clauses(clause.Returning{}).
+		Where("publish_at <= ? AND (unpublish_at IS NULL OR unpublish_at > ?)", now, now).
+		Updates(map[string]interface{}{
+			"is_published": true,
+			"published_at": gorm.Expr("COALESCE(published_at, publish_at)"),
+			"publish_at":   nil,
+		}).Error
+	return posts, err
+}
+
+// UnpublishDue withdraws posts whose UnpublishAt has passed and returns them.
+// A publication that was due before the withdrawal is dropped with it.
+func (r *repository) UnpublishDue(now time.Time) ([]Post, error) {
+	var posts []Post
+	err := r.db.Model(&posts).Clauses(clause.Returning{}).
+		Where("unpublish_at <= ?", now).
+		Updates(map[string]interface{}{
+			"is_published": false,
+			"unpublish_at": nil,
+			"publish_at":   gorm.Expr("CASE WHEN publish_at <= ? THEN NULL ELSE publish_at END", now),
+		}).Error
+	return posts, err
+}
+
+func (r *repository) FindTagBySlug(slug string) (*Tag, error) {
+	var tag Tag
+	err := r.db.First(&tag, "slug = ?", slug).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// withTag limits a query to posts with the tag, or leaves it alone when
+// tagSlug is empty.
+func withTag(query *gorm.DB, tagSlug string) *gorm.DB {
+	if tagSlug == "" {
+		return query
+	}
+	return query.Where("posts.id IN (SELECT post_tags.post_id FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE tags.slug = ?)", tagSlug)
+}
+
+// FindPublished returns the most recently published visible posts, newest
+// first, optionally only those with a tag.
+func (r *repository) FindPublished(tagSlug string, limit int) ([]Post, error) {
+	var posts []Post
+	query := visible(r.db.Preload("Tags").Preload("Images"), time.Now())
+	query = withTag(query, tagSlug).Order("COALESCE(published_at, publish_at, created_at) DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&posts).Error
+	return posts, err
+}
+
+// PublishedVersion returns the latest change to the posts, optionally only
+// those with a tag, and how many of them are visible. Changes include drafts
+// and scheduled publications and withdrawals that have come due, so posts
+// entering or leaving the visible set move the time too. Together they change
+// whenever the published set does.
+func (r *repository) PublishedVersion(tagSlug string) (time.Time, int64, error) {
+	now := time.Now()
+	var count int64
+	if err := withTag(visible(r.db.Model(&Post{}), now), tagSlug).Count(&count).Error; err != nil {
+		return time.Time{}, 0, err
+	}
+
+	var version struct {
+		LastModified *time.Time
+	}
+	err := withTag(r.db.Model(&Post{}), tagSlug).
+		Select(`MAX(GREATEST(updated_at,
+			CASE WHEN publish_at <= ? THEN publish_at END,
+			CASE WHEN unpublish_at <= ? THEN unpublish_at END)) AS last_modified`, now, now).
+		Scan(&version).Error
+	if err != nil || version.LastModified == nil {
+		return time.Time{}, count, err
+	}
+	return *version.LastModified, count, nil
+}
+
+// seriesPartRow is a row of FindSeriesParts.
+type seriesPartRow struct {
+	PostID      uuid.UUID
+	SeriesID    uuid.UUID
+	SeriesTitle string
+	SeriesSlug  string
+	Part        int
+	Total       int
+	PrevTitle   *string
+	PrevSlug    *string
+	NextTitle   *string
+	NextSlug    *string
+}
+
+// FindSeriesParts returns where each of the posts stands in its series,
+// keyed by post ID. Parts are numbered among the visible posts of the
+// series, so the links never lead to a draft. Posts in no series are left
+// out.
+func (r *repository) FindSeriesParts(postIDs []uuid.UUID) (map[uuid.UUID]*SeriesPart, error) {
+	parts := make(map[uuid.UUID]*SeriesPart)
+	if len(postIDs) == 0 {
+		return parts, nil
+	}
+
+	var rows []seriesPartRow
+	err := r.db.Raw(`
+		WITH parts AS (
+			SELECT series_posts.series_id, series_posts.post_id,
+				ROW_NUMBER() OVER w AS part,
+				COUNT(*) OVER (PARTITION BY series_posts.series_id) AS total,
+				LAG(posts.title) OVER w AS prev_title, LAG(posts.slug) OVER w AS prev_slug,
+				LEAD(posts.title) OVER w AS next_title, LEAD(posts.slug) OVER w AS next_slug
+			FROM series_posts
+			JOIN posts ON posts.id = series_posts.post_id
+				AND (posts.is_published OR posts.publish_at <= @now)
+				AND (posts.unpublish_at IS NULL OR posts.unpublish_at > @now)
+			WHERE series_posts.series_id IN (SELECT series_id FROM series_posts WHERE post_id IN @ids)
+			WINDOW w AS (PARTITION BY series_posts.series_id ORDER BY series_posts.position)
+		)
+		SELECT parts.*, series.title AS series_title, series.slug AS series_slug
+		FROM parts JOIN series ON series.id = parts.series_id
+		WHERE parts.post_id IN @ids`,
+		map[string]interface{}{"now": time.Now(), "ids": postIDs}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		part := &SeriesPart{
+			ID:    row.SeriesID,
+			Title: row.SeriesTitle,
+			Slug:  row.SeriesSlug,
+			Part:  row.Part,
+			Total: row.Total,
+		}
+		if row.PrevSlug != nil {
+			part.Previous = &PartLink{Title: *row.PrevTitle, Slug: *row.PrevSlug}
+		}
+		if row.NextSlug != nil {
+			part.Next = &PartLink{Title: *row.NextTitle, Slug: *row.NextSlug}
+		}
+		parts[row.PostID] = part
+	}
+	return parts, nil
+}
+
+// RenderMissing stores the rendered content of posts saved before it was
+// cached on save, and indexes them again, so the HTML, table of contents and
+// search snippets no longer depend on the raw markdown. The migration runs it
+// at startup; once every row has been rendered it finds nothing to do.
+func RenderMissing(db *gorm.DB) error {
+	var posts []Post
+	return db.Select("id", "content_markdown").
+		Where("COALESCE(content_html, '') = '' AND COALESCE(content_markdown, '') <> ''").
+		FindInBatches(&posts, 100, func(tx *gorm.DB, batch int) error {
+			for i := range posts {
+				post := &posts[i]
+				if err := post.Render(); err != nil {
+					return err
+				}
+				err := db.Model(post).
+					Select("content_html", "table_of_contents", "word_count", "reading_time").
+					UpdateColumns(post).Error
+				if err != nil {
+					return err
+				}
+				if err := search.Index(db, "posts", post.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -2,66 +2,209 @@ package posts
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/indexnow"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/modules/images"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
 )
 
 type Service interface {
-	Create(req *CreatePostRequest) (*Post, error)
-	Update(id uuid.UUID, req *UpdatePostRequest) (*Post, error)
-	Delete(id uuid.UUID) error
+	Create(actor audit.Actor, req *CreatePostRequest) (*Post, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdatePostRequest) (*Post, error)
+	Delete(actor audit.Actor, id uuid.UUID) error
 	GetByID(id uuid.UUID) (*Post, error)
 	GetBySlug(slug string) (*Post, error)
+	ResolveSlug(oldSlug string) (string, error)
 	GetAll(public bool) ([]Post, error)
 	GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error)
+	GetScheduled() ([]Post, error)
+	GetPublished(tagSlug string, limit int) ([]Post, error)
+	GetPublishedVersion(tagSlug string) (time.Time, int64, error)
+	ApplySchedule(now time.Time) error
+	RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Post, error)
 }
 
+var (
+	// ErrInvalidSchedule is returned when a post would be withdrawn before it
+	// is published.
+	ErrInvalidSchedule = errors.New("unpublish_at must be after publish_at")
+	ErrPostNotFound    = errors.New("post not found")
+	ErrTagNotFound     = errors.New("tag not found")
+)
+
 type service struct {
 	repo       Repository
 	imagesRepo images.Repository
+	audit      audit.Service
+	revisions  revisions.Service
+	slugs      slugs.Service
+	site       config.SiteConfig
+	indexNow   indexnow.Notifier
 }
 
-func NewService(repo Repository, imagesRepo images.Repository) Service {
+func NewService(repo Repository, imagesRepo images.Repository, auditService audit.Service, revisionService revisions.Service, slugService slugs.Service, site config.SiteConfig, notifier indexnow.Notifier) Service {
 	return &service{
 		repo:       repo,
 		imagesRepo: imagesRepo,
+		audit:      auditService,
+		revisions:  revisionService,
+		slugs:      slugService,
+		site:       site,
+		indexNow:   notifier,
+	}
+}
+
+// postRevision is the part of a post kept in its revisions and put back when
+// one is restored. Publication state and images are not versioned.
+type postRevision struct {
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	SlugPinned      *bool    `json:"slug_pinned,omitempty"` // nil in revisions recorded before it was kept
+	ContentMarkdown string   `json:"content_markdown"`
+	Summary         string   `json:"summary"`
+	Tags            []string `json:"tags"`
+}
+
+func (p *Post) revisionState() postRevision {
+	tags := make([]string, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		tags = append(tags, tag.Name)
+	}
+	sort.Strings(tags)
+
+	slugPinned := p.SlugPinned
+	return postRevision{
+		Title:           p.Title,
+		Slug:            p.Slug,
+		SlugPinned:      &slugPinned,
+		ContentMarkdown: p.ContentMarkdown,
+		Summary:         p.Summary,
+		Tags:            tags,
 	}
 }
 
+// notifyChanged tells search engines about a change to the public page of
+// a post: one visible before or after the change. A new slug moves the page,
+// so the old URL is submitted as well.
+func (s *service) notifyChanged(before, after *Post) {
+	now := time.Now()
+	var urls []string
+	if before != nil && before.IsVisible(now) {
+		urls = append(urls, s.site.PostURL(before.Slug))
+	}
+	if after != nil && after.IsVisible(now) {
+		urls = append(urls, s.site.PostURL(after.Slug))
+	}
+	s.indexNow.Notify(urls...)
+}
+
 type CreatePostRequest struct {
 	Title           string                     `json:"title"`
+	Slug            *string                    `json:"slug"` // custom slug, pinned against title changes
 	ContentMarkdown string                     `json:"content_markdown"`
 	Summary         string                     `json:"summary"`
 	IsPublished     bool                       `json:"is_published"`
+	PublishAt       *time.Time                 `json:"publish_at"`   // publish later; overrides is_published
+	UnpublishAt     *time.Time                 `json:"unpublish_at"` // withdraw automatically
 	Tags            []string                   `json:"tags"`
 	Images          []images.ImageUploadResult `json:"images"`
 }
 
 type UpdatePostRequest struct {
 	Title           string                     `json:"title"`
+	Slug            *string                    `json:"slug"` // custom slug, pinned; "" unpins it, omitted keeps it
 	ContentMarkdown string                     `json:"content_markdown"`
 	Summary         string                     `json:"summary"`
 	IsPublished     bool                       `json:"is_published"`
+	PublishAt       *time.Time                 `json:"publish_at"`   // publish later; overrides is_published
+	UnpublishAt     *time.Time                 `json:"unpublish_at"` // withdraw automatically
 	Tags            []string                   `json:"tags"`
 	Images          []images.ImageUploadResult `json:"images"`
 }
 
-func (s *service) Create(req *CreatePostRequest) (*Post, error) {
+// assignSlug sets the slug of post. A custom slug must be valid and unused;
+// it is pinned, so it is kept when the title changes, until an empty custom
+// slug unpins it again. Otherwise a free slug is generated from the title.
+func (s *service) assignSlug(post *Post, custom *string) error {
+	if custom != nil {
+		if *custom != "" {
+			if err := s.slugs.Check(slugs.EntityPost, post.ID, *custom); err != nil {
+				return err
+			}
+			post.Slug = *custom
+			post.SlugPinned = true
+			return nil
+		}
+		post.SlugPinned = false
+	}
+	if post.SlugPinned {
+		return nil
+	}
+
+	generated, err := s.slugs.Generate(slugs.EntityPost, post.ID, post.Title, post.Slug)
+	if err != nil {
+		return err
+	}
+	post.Slug = generated
+	return nil
+}
+
+// applySchedule sets the publication state of post. A PublishAt in the
+// future keeps the post unpublished until then; one in the past publishes
+// it now. The same goes for UnpublishAt.
+func applySchedule(post *Post, isPublished bool, publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return ErrInvalidSchedule
+	}
+
+	now := time.Now()
+	post.IsPublished = isPublished
+	post.PublishAt = publishAt
+	post.UnpublishAt = unpublishAt
+
+	if post.PublishAt != nil {
+		if post.PublishAt.After(now) {
+			post.IsPublished = false
+		} else {
+			post.IsPublished = true
+			if post.PublishedAt == nil {
+				publishedAt := *post.PublishAt
+				post.PublishedAt = &publishedAt
+			}
+			post.PublishAt = nil
+		}
+	}
+	if post.UnpublishAt != nil && !post.UnpublishAt.After(now) {
+		post.IsPublished = false
+		post.UnpublishAt = nil
+	}
+
+	if post.IsPublished && post.PublishedAt == nil {
+		post.PublishedAt = &now
+	}
+	return nil
+}
+
+func (s *service) Create(actor audit.Actor, req *CreatePostRequest) (*Post, error) {
 	post := &Post{
 		Title:           req.Title,
-		Slug:            slug.Make(req.Title),
 		ContentMarkdown: req.ContentMarkdown,
 		Summary:         req.Summary,
-		IsPublished:     req.IsPublished,
 	}
 
-	if req.IsPublished {
-		now := time.Now()
-		post.PublishedAt = &now
+	if err := applySchedule(post, req.IsPublished, req.PublishAt, req.UnpublishAt); err != nil {
+		return nil, err
+	}
+	if err := s.assignSlug(post, req.Slug); err != nil {
+		return nil, err
 	}
 
 	// Handle tags (find or create)
@@ -75,6 +218,10 @@ func (s *service) Create(req *CreatePostRequest) (*Post, error) {
 	}
 	post.Tags = tags
 
+	if err := post.Render(); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Create(post); err != nil {
 		return nil, err
 	}
@@ -95,29 +242,33 @@ func (s *service) Create(req *CreatePostRequest) (*Post, error) {
 		_ = s.imagesRepo.Create(image)
 	}
 
+	s.audit.Record(actor, audit.ActionCreate, "post", post.ID.String(), nil, post)
+	s.revisions.Record(actor, revisions.EntityPost, post.ID, nil, post.revisionState())
+	s.slugs.Record(slugs.EntityPost, post.ID, "", post.Slug)
+	s.notifyChanged(nil, post)
+
 	return post, nil
 }
 
-func (s *service) Update(id uuid.UUID, req *UpdatePostRequest) (*Post, error) {
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdatePostRequest) (*Post, error) {
 	post, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if post == nil {
-		return nil, errors.New("post not found")
+		return nil, ErrPostNotFound
 	}
+	before := *post
 
 	post.Title = req.Title
-	if req.Title != "" {
-		post.Slug = slug.Make(req.Title)
-	}
 	post.ContentMarkdown = req.ContentMarkdown
 	post.Summary = req.Summary
-	post.IsPublished = req.IsPublished
 
-	if req.IsPublished && post.PublishedAt == nil {
-		now := time.Now()
-		post.PublishedAt = &now
+	if err := applySchedule(post, req.IsPublished, req.PublishAt, req.UnpublishAt); err != nil {
+		return nil, err
+	}
+	if err := s.assignSlug(post, req.Slug); err != nil {
+		return nil, err
 	}
 
 	// Update tags
@@ -131,6 +282,10 @@ func (s *service) Update(id uuid.UUID, req *UpdatePostRequest) (*Post, error) {
 	}
 	post.Tags = tags
 
+	if err := post.Render(); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Update(post); err != nil {
 		return nil, err
 	}
@@ -153,11 +308,31 @@ func (s *service) Update(id uuid.UUID, req *UpdatePostRequest) (*Post, error) {
 		}
 	}
 
+	s.audit.Record(actor, audit.ActionUpdate, "post", post.ID.String(), before, post)
+	s.revisions.Record(actor, revisions.EntityPost, post.ID, before.revisionState(), post.revisionState())
+	s.slugs.Record(slugs.EntityPost, post.ID, before.Slug, post.Slug)
+	s.notifyChanged(&before, post)
+
 	return post, nil
 }
 
-func (s *service) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *service) Delete(actor audit.Actor, id uuid.UUID) error {
+	post, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	if post != nil {
+		s.audit.Record(actor, audit.ActionDelete, "post", post.ID.String(), post, nil)
+		s.notifyChanged(post, nil)
+	}
+	s.revisions.DeleteDrafts(revisions.EntityPost, id)
+	s.slugs.Forget(slugs.EntityPost, id)
+	return nil
 }
 
 func (s *service) GetByID(id uuid.UUID) (*Post, error) {
@@ -165,11 +340,60 @@ func (s *service) GetByID(id uuid.UUID) (*Post, error) {
 }
 
 func (s *service) GetBySlug(slug string) (*Post, error) {
-	return s.repo.FindBySlug(slug)
+	post, err := s.repo.FindBySlug(slug, true)
+	if err != nil || post == nil {
+		return post, err
+	}
+	if err := s.attachSeries([]*Post{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// ResolveSlug returns the current slug of the visible post that used to be
+// at oldSlug, or an empty string when there is none.
+func (s *service) ResolveSlug(oldSlug string) (string, error) {
+	id, err := s.slugs.Resolve(slugs.EntityPost, oldSlug)
+	if err != nil || id == nil {
+		return "", err
+	}
+	post, err := s.repo.FindByID(*id)
+	if err != nil || post == nil || !post.IsVisible(time.Now()) {
+		return "", err
+	}
+	return post.Slug, nil
 }
 
 func (s *service) GetAll(public bool) ([]Post, error) {
-	return s.repo.FindAll(public, 0, 0)
+	posts, err := s.repo.FindAll(public, 0, 0)
+	if err != nil || !public {
+		return posts, err
+	}
+	list := make([]*Post, len(posts))
+	for i := range posts {
+		list[i] = &posts[i]
+	}
+	if err := s.attachSeries(list); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// attachSeries sets the series navigation of the posts that belong to a
+// series.
+func (s *service) attachSeries(posts []*Post) error {
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	parts, err := s.repo.FindSeriesParts(ids)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		post.Series = parts[post.ID]
+	}
+	return nil
 }
 
 func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error) {
@@ -191,3 +415,132 @@ func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, e
 	res := pagination.NewResponse(posts, total, p)
 	return &res, nil
 }
+
+// GetScheduled returns posts with a pending publication or withdrawal.
+func (s *service) GetScheduled() ([]Post, error) {
+	return s.repo.FindScheduled()
+}
+
+// GetPublished returns the latest visible posts, newest first. With a tag
+// slug only posts with that tag are included; an unknown tag is an error.
+func (s *service) GetPublished(tagSlug string, limit int) ([]Post, error) {
+	if err := s.checkTag(tagSlug); err != nil {
+		return nil, err
+	}
+	return s.repo.FindPublished(tagSlug, limit)
+}
+
+// GetPublishedVersion returns when the visible posts, optionally with a tag,
+// last changed and how many there are, so callers can tell whether what
+// they served before is still current.
+func (s *service) GetPublishedVersion(tagSlug string) (time.Time, int64, error) {
+	if err := s.checkTag(tagSlug); err != nil {
+		return time.Time{}, 0, err
+	}
+	return s.repo.PublishedVersion(tagSlug)
+}
+
+func (s *service) checkTag(tagSlug string) error {
+	if tagSlug == "" {
+		return nil
+	}
+	tag, err := s.repo.FindTagBySlug(tagSlug)
+	if err != nil {
+		return err
+	}
+	if tag == nil {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// ApplySchedule publishes and withdraws posts whose scheduled time has
+// passed. It is run periodically by the scheduler; changes are recorded in
+// the audit log as made by the system.
+func (s *service) ApplySchedule(now time.Time) error {
+	published, err := s.repo.PublishDue(now)
+	if err != nil {
+		return err
+	}
+	var urls []string
+	for _, post := range published {
+		urls = append(urls, s.site.PostURL(post.Slug))
+		s.audit.Record(audit.Actor{}, audit.ActionPublish, "post", post.ID.String(),
+			map[string]interface{}{"IsPublished": false}, map[string]interface{}{"IsPublished": true})
+	}
+
+	unpublished, err := s.repo.UnpublishDue(now)
+	if err != nil {
+		return err
+	}
+	for _, post := range unpublished {
+		urls = append(urls, s.site.PostURL(post.Slug))
+		s.audit.Record(audit.Actor{}, audit.ActionUnpublish, "post", post.ID.String(),
+			map[string]interface{}{"IsPublished": true}, map[string]interface{}{"IsPublished": false})
+	}
+	s.indexNow.Notify(urls...)
+	return nil
+}
+
+// RestoreRevision puts the fields saved in a revision back into the post
+// and records the result as a new revision.
+func (s *service) RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Post, error) {
+	post, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+
+	revision, err := s.revisions.Get(revisions.EntityPost, id, number)
+	if err != nil {
+		return nil, err
+	}
+	var state postRevision
+	if err := revisions.Decode(revision, &state); err != nil {
+		return nil, err
+	}
+	before := *post
+
+	post.Title = state.Title
+	post.Slug = state.Slug
+	if state.SlugPinned != nil {
+		post.SlugPinned = *state.SlugPinned
+	}
+	// Another post may have taken the slug since; the generated one follows
+	// the title again
+	if err := s.slugs.Check(slugs.EntityPost, post.ID, post.Slug); err != nil {
+		if post.Slug, err = s.slugs.Generate(slugs.EntityPost, post.ID, post.Title, ""); err != nil {
+			return nil, err
+		}
+		post.SlugPinned = false
+	}
+	post.ContentMarkdown = state.ContentMarkdown
+	post.Summary = state.Summary
+
+	var tags []*Tag
+	for _, tagName := range state.Tags {
+		tag, err := s.repo.FindOrCreateTag(tagName, slug.Make(tagName))
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	post.Tags = tags
+
+	if err := post.Render(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(post); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionRestore, "post", post.ID.String(), before, post)
+	s.revisions.RecordRestore(actor, revisions.EntityPost, post.ID, post.revisionState(), revision.Number)
+	s.slugs.Record(slugs.EntityPost, post.ID, before.Slug, post.Slug)
+	s.notifyChanged(&before, post)
+
+	return post, nil
+}
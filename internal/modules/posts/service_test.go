@@ -0,0 +1,180 @@
+package posts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/images"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+)
+
+// recordingNotifier keeps the URLs of every Notify call that had any.
+type recordingNotifier struct {
+	pings [][]string
+}
+
+func (n *recordingNotifier) Notify(urls ...string) {
+	if len(urls) > 0 {
+		n.pings = append(n.pings, urls)
+	}
+}
+
+// fakePostRepo keeps posts in memory. Other repository methods are not
+// used and panic.
+type fakePostRepo struct {
+	Repository
+	posts map[uuid.UUID]*Post
+}
+
+func (r *fakePostRepo) Create(post *Post) error {
+	post.ID = uuid.New()
+	stored := *post
+	r.posts[post.ID] = &stored
+	return nil
+}
+
+func (r *fakePostRepo) Update(post *Post) error {
+	stored := *post
+	r.posts[post.ID] = &stored
+	return nil
+}
+
+func (r *fakePostRepo) Delete(id uuid.UUID) error {
+	delete(r.posts, id)
+	return nil
+}
+
+func (r *fakePostRepo) FindByID(id uuid.UUID) (*Post, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *post
+	return &found, nil
+}
+
+type nopImages struct{ images.Repository }
+
+func (nopImages) Create(image *images.Image) error                           { return nil }
+func (nopImages) DeleteByEntity(entityType string, entityID uuid.UUID) error { return nil }
+
+type nopAudit struct{ audit.Service }
+
+func (nopAudit) Record(actor audit.Actor, action, entityType, entityID string, before, after interface{}) {
+}
+
+type nopRevisions struct{ revisions.Service }
+
+func (nopRevisions) Record(actor audit.Actor, entityType string, entityID uuid.UUID, before, after interface{}) {
+}
+func (nopRevisions) DeleteDrafts(entityType string, entityID uuid.UUID) {}
+
+// titleSlugs uses the title as the slug.
+type titleSlugs struct{ slugs.Service }
+
+func (titleSlugs) Generate(entityType string, entityID uuid.UUID, title, current string) (string, error) {
+	return title, nil
+}
+func (titleSlugs) Record(entityType string, entityID uuid.UUID, oldSlug, newSlug string) {}
+func (titleSlugs) Forget(entityType string, entityID uuid.UUID)                          {}
+
+func TestIndexNowNotifications(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		create *CreatePostRequest // the post before the change
+		update *UpdatePostRequest // nil to delete the post instead
+		want   []string
+	}{
+		{
+			name:   "draft updated",
+			create: &CreatePostRequest{Title: "draft"},
+			update: &UpdatePostRequest{Title: "draft"},
+		},
+		{
+			name:   "draft renamed",
+			create: &CreatePostRequest{Title: "draft"},
+			update: &UpdatePostRequest{Title: "renamed"},
+		},
+		{
+			name:   "draft deleted",
+			create: &CreatePostRequest{Title: "draft"},
+		},
+		{
+			name:   "scheduled post rescheduled",
+			create: &CreatePostRequest{Title: "later", PublishAt: &tomorrow},
+			update: &UpdatePostRequest{Title: "later", PublishAt: &tomorrow},
+		},
+		{
+			name:   "draft published",
+			create: &CreatePostRequest{Title: "draft"},
+			update: &UpdatePostRequest{Title: "draft", IsPublished: true},
+			want:   []string{"https://example.com/blog/draft"},
+		},
+		{
+			name:   "draft published by a past date",
+			create: &CreatePostRequest{Title: "draft"},
+			update: &UpdatePostRequest{Title: "draft", PublishAt: &yesterday},
+			want:   []string{"https://example.com/blog/draft"},
+		},
+		{
+			name:   "published post renamed",
+			create: &CreatePostRequest{Title: "hello", IsPublished: true},
+			update: &UpdatePostRequest{Title: "renamed", IsPublished: true},
+			want:   []string{"https://example.com/blog/hello", "https://example.com/blog/renamed"},
+		},
+		{
+			name:   "published post withdrawn",
+			create: &CreatePostRequest{Title: "hello", IsPublished: true},
+			update: &UpdatePostRequest{Title: "hello"},
+			want:   []string{"https://example.com/blog/hello"},
+		},
+		{
+			name:   "published post deleted",
+			create: &CreatePostRequest{Title: "hello", IsPublished: true},
+			want:   []string{"https://example.com/blog/hello"},
+		},
+	}
+
+	site := config.SiteConfig{URL: "https://example.com", PostPath: "/blog/{slug}"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &recordingNotifier{}
+			repo := &fakePostRepo{posts: map[uuid.UUID]*Post{}}
+			svc := NewService(repo, nopImages{}, nopAudit{}, nopRevisions{}, titleSlugs{}, site, notifier)
+
+			post, err := svc.Create(audit.Actor{}, tt.create)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if got := len(notifier.pings) == 1; got != tt.create.IsPublished {
+				t.Fatalf("create pinged %v, want a ping only for a published post", notifier.pings)
+			}
+			notifier.pings = nil
+
+			if tt.update != nil {
+				_, err = svc.Update(audit.Actor{}, post.ID, tt.update)
+			} else {
+				err = svc.Delete(audit.Actor{}, post.ID)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want [][]string
+			if tt.want != nil {
+				want = [][]string{tt.want}
+			}
+			if !reflect.DeepEqual(notifier.pings, want) {
+				t.Errorf("pings = %v, want %v", notifier.pings, want)
+			}
+		})
+	}
+}
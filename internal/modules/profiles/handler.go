@@ -1,10 +1,14 @@
 package profiles
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
 
@@ -95,7 +99,7 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		req.ResumeFile = resumeFile
 	}
 
-	profile, err := h.service.CreateOrUpdateProfile(userID, &req)
+	profile, err := h.service.CreateOrUpdateProfile(audit.ActorFromContext(c), userID, &req)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update profile", err.Error())
 		return
@@ -103,3 +107,40 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Profile updated successfully", profile)
 }
+
+// RestoreProfileRevision godoc
+// @Summary      Admin - Restore Profile Revision
+// @Description  Put the fields saved in a revision back into the profile. The result is stored as a new revision; history is never rewritten.
+// @Tags         Admin - Profile
+// @Produce      json
+// @Param        id      path     string  true  "Profile ID"
+// @Param        number  path     int     true  "Revision number"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/profile/{id}/revisions/{number}/restore [post]
+func (h *Handler) RestoreProfileRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid revision number", "revision number must be a positive integer")
+		return
+	}
+
+	profile, err := h.service.RestoreRevision(audit.ActorFromContext(c), id, number)
+	if err != nil {
+		if errors.Is(err, ErrProfileNotFound) || errors.Is(err, revisions.ErrRevisionNotFound) {
+			response.Error(c, http.StatusNotFound, "Failed to restore profile", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to restore profile", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Profile restored successfully", profile)
+}
@@ -10,6 +10,7 @@ import (
 type Repository interface {
 	GetProfile() (*Profile, error)
 	GetProfileByUserID(userID uuid.UUID) (*Profile, error)
+	FindByID(id uuid.UUID) (*Profile, error)
 	Update(profile *Profile) error
 	Create(profile *Profile) error
 }
@@ -51,6 +52,18 @@ func (r *repository) Create(profile *Profile) error {
 	return r.db.Create(profile).Error
 }
 
+func (r *repository) FindByID(id uuid.UUID) (*Profile, error) {
+	var profile Profile
+	err := r.db.First(&profile, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
 func (r *repository) Update(profile *Profile) error {
 	return r.db.Save(profile).Error
 }
@@ -11,26 +11,47 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
 )
 
 type Service interface {
 	GetProfile() (*Profile, error)
 	GetProfileByUserID(userID uuid.UUID) (*Profile, error)
-	CreateOrUpdateProfile(userID uuid.UUID, req *UpdateProfileRequest) (*Profile, error)
+	CreateOrUpdateProfile(actor audit.Actor, userID uuid.UUID, req *UpdateProfileRequest) (*Profile, error)
+	RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Profile, error)
 }
 
+var ErrProfileNotFound = errors.New("profile not found")
+
 type service struct {
-	repo    Repository
-	storage string
+	repo      Repository
+	storage   string
+	audit     audit.Service
+	revisions revisions.Service
 }
 
-func NewService(repo Repository) Service {
+func NewService(repo Repository, auditService audit.Service, revisionService revisions.Service) Service {
 	return &service{
-		repo:    repo,
-		storage: "storage",
+		repo:      repo,
+		storage:   "storage",
+		audit:     auditService,
+		revisions: revisionService,
 	}
 }
 
+// profileRevision is the part of a profile kept in its revisions and put
+// back when one is restored. Avatar and resume files are not versioned,
+// since a replaced file is deleted from storage.
+type profileRevision struct {
+	FullName string `json:"full_name"`
+	Bio      string `json:"bio"`
+}
+
+func (p *Profile) revisionState() profileRevision {
+	return profileRevision{FullName: p.FullName, Bio: p.Bio}
+}
+
 func (s *service) GetProfile() (*Profile, error) {
 	return s.repo.GetProfile()
 }
@@ -40,10 +61,10 @@ func (s *service) GetProfileByUserID(userID uuid.UUID) (*Profile, error) {
 }
 
 type UpdateProfileRequest struct {
-	FullName   string                `form:"full_name"`
-	Bio        string                `form:"bio"`
-	AvatarFile *multipart.FileHeader `form:"avatar"`
-	ResumeFile *multipart.FileHeader `form:"resume"`
+	FullName   string                `form:"full_name" json:"full_name"`
+	Bio        string                `form:"bio" json:"bio"`
+	AvatarFile *multipart.FileHeader `form:"avatar" json:"-"`
+	ResumeFile *multipart.FileHeader `form:"resume" json:"-"`
 }
 
 // allowedImageExts defines allowed extensions for avatar images
@@ -132,16 +153,20 @@ func (s *service) deleteOldFile(fileURL string) {
 	}
 }
 
-func (s *service) CreateOrUpdateProfile(userID uuid.UUID, req *UpdateProfileRequest) (*Profile, error) {
+func (s *service) CreateOrUpdateProfile(actor audit.Actor, userID uuid.UUID, req *UpdateProfileRequest) (*Profile, error) {
 	profile, err := s.repo.GetProfileByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	var before *Profile
 	if profile == nil {
 		profile = &Profile{
 			UserID: userID,
 		}
+	} else {
+		snapshot := *profile
+		before = &snapshot
 	}
 
 	profile.FullName = req.FullName
@@ -171,7 +196,9 @@ func (s *service) CreateOrUpdateProfile(userID uuid.UUID, req *UpdateProfileRequ
 	}
 	// If no new resume file, keep existing ResumeURL unchanged
 
+	action := audit.ActionUpdate
 	if profile.ID == uuid.Nil {
+		action = audit.ActionCreate
 		err = s.repo.Create(profile)
 	} else {
 		err = s.repo.Update(profile)
@@ -181,5 +208,47 @@ func (s *service) CreateOrUpdateProfile(userID uuid.UUID, req *UpdateProfileRequ
 		return nil, err
 	}
 
+	s.audit.Record(actor, action, "profile", profile.ID.String(), before, profile)
+
+	var beforeState interface{}
+	if before != nil {
+		beforeState = before.revisionState()
+	}
+	s.revisions.Record(actor, revisions.EntityProfile, profile.ID, beforeState, profile.revisionState())
+
+	return profile, nil
+}
+
+// RestoreRevision puts the fields saved in a revision back into the profile
+// and records the result as a new revision.
+func (s *service) RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Profile, error) {
+	profile, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrProfileNotFound
+	}
+
+	revision, err := s.revisions.Get(revisions.EntityProfile, id, number)
+	if err != nil {
+		return nil, err
+	}
+	var state profileRevision
+	if err := revisions.Decode(revision, &state); err != nil {
+		return nil, err
+	}
+	before := *profile
+
+	profile.FullName = state.FullName
+	profile.Bio = state.Bio
+
+	if err := s.repo.Update(profile); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionRestore, "profile", profile.ID.String(), before, profile)
+	s.revisions.RecordRestore(actor, revisions.EntityProfile, profile.ID, profile.revisionState(), revision.Number)
+
 	return profile, nil
 }
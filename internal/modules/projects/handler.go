@@ -1,10 +1,17 @@
 package projects
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
 )
@@ -19,7 +26,7 @@ func NewHandler(service Service) *Handler {
 
 // GetPublicProjects godoc
 // @Summary      Public - Get All Projects
-// @Description  Retrieve a list of all projects
+// @Description  Retrieve a list of all projects inside their visibility window
 // @Tags         Public - Projects
 // @Produce      json
 // @Success      200  {array}   Project
@@ -68,7 +75,42 @@ func (h *Handler) GetPublicProjectByID(c *gin.Context) {
 		response.Error(c, http.StatusInternalServerError, "Failed to fetch project", err.Error())
 		return
 	}
-	if project == nil {
+	if project == nil || !project.IsVisible(time.Now()) {
+		response.Error(c, http.StatusNotFound, "Project not found", "project not found")
+		return
+	}
+	response.Success(c, http.StatusOK, "Project fetched successfully", project)
+}
+
+// GetPublicProjectBySlug godoc
+// @Summary      Public - Get Project by Slug
+// @Description  Retrieve a visible project by its slug. A slug the project had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.
+// @Tags         Public - Projects
+// @Produce      json
+// @Param        slug  path      string  true  "Project slug"
+// @Success      200   {object}  Project
+// @Success      301   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /public/projects/slug/{slug} [get]
+func (h *Handler) GetPublicProjectBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	project, err := h.service.GetBySlug(slug)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch project", err.Error())
+		return
+	}
+	if project == nil || !project.IsVisible(time.Now()) {
+		current, err := h.service.ResolveSlug(slug)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch project", err.Error())
+			return
+		}
+		if current != "" {
+			c.Header("Location", "/api/public/projects/slug/"+url.PathEscape(current))
+			response.Success(c, http.StatusMovedPermanently, "Project moved", gin.H{"slug": current})
+			return
+		}
 		response.Error(c, http.StatusNotFound, "Project not found", "project not found")
 		return
 	}
@@ -77,7 +119,7 @@ func (h *Handler) GetPublicProjectByID(c *gin.Context) {
 
 // CreateProject godoc
 // @Summary      Admin - Create Project
-// @Description  Create a new project
+// @Description  Create a new project. visible_from and visible_until limit when it is shown publicly. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken.
 // @Tags         Admin - Projects
 // @Accept       json
 // @Produce      json
@@ -85,6 +127,7 @@ func (h *Handler) GetPublicProjectByID(c *gin.Context) {
 // @Security     BearerAuth
 // @Success      201  {object}  Project
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/projects [post]
 func (h *Handler) CreateProject(c *gin.Context) {
@@ -94,8 +137,20 @@ func (h *Handler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.service.Create(&req)
+	project, err := h.service.Create(audit.ActorFromContext(c), &req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidSchedule) {
+			response.Error(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrInvalidSlug) || errors.Is(err, slugs.ErrReservedSlug) {
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrSlugTaken) {
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to create project", err.Error())
 		return
 	}
@@ -113,6 +168,7 @@ func (h *Handler) CreateProject(c *gin.Context) {
 // @Security     BearerAuth
 // @Success      200  {object}  Project
 // @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/projects/{id} [put]
 func (h *Handler) UpdateProject(c *gin.Context) {
@@ -129,8 +185,20 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.service.Update(id, &req)
+	project, err := h.service.Update(audit.ActorFromContext(c), id, &req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidSchedule) {
+			response.Error(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrInvalidSlug) || errors.Is(err, slugs.ErrReservedSlug) {
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+			return
+		}
+		if errors.Is(err, slugs.ErrSlugTaken) {
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to update project", err.Error())
 		return
 	}
@@ -156,9 +224,46 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(audit.ActorFromContext(c), id); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete project", err.Error())
 		return
 	}
 	response.Success(c, http.StatusOK, "Project deleted successfully", nil)
 }
+
+// RestoreProjectRevision godoc
+// @Summary      Admin - Restore Project Revision
+// @Description  Put the fields saved in a revision back into the project. The result is stored as a new revision; history is never rewritten.
+// @Tags         Admin - Projects
+// @Produce      json
+// @Param        id      path     string  true  "Project ID"
+// @Param        number  path     int     true  "Revision number"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/projects/{id}/revisions/{number}/restore [post]
+func (h *Handler) RestoreProjectRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid revision number", "revision number must be a positive integer")
+		return
+	}
+
+	project, err := h.service.RestoreRevision(audit.ActorFromContext(c), id, number)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) || errors.Is(err, revisions.ErrRevisionNotFound) {
+			response.Error(c, http.StatusNotFound, "Failed to restore project", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to restore project", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Project restored successfully", project)
+}
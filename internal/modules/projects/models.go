@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/markdown"
 	"github.com/prakoso-id/personal-backend/internal/modules/images"
 	"github.com/prakoso-id/personal-backend/internal/modules/skills"
 )
@@ -12,14 +13,21 @@ type Project struct {
 	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	Title           string          `gorm:"type:varchar(255);not null"`
 	Slug            string          `gorm:"type:varchar(255);unique;not null"`
+	SlugPinned      bool            `gorm:"default:false"` // custom slug, kept when the title changes
 	Description     string          `gorm:"type:text"`
 	ContentMarkdown string          `gorm:"type:text"`
+	ContentHTML     string          `gorm:"type:text"` // rendered from ContentMarkdown on save
+	TableOfContents markdown.TOC    `gorm:"type:jsonb;serializer:json"`
+	WordCount       int             `gorm:"default:0"`
+	ReadingTime     int             `gorm:"default:0"` // minutes
 	DemoURL         string          `gorm:"type:varchar(255)"`
 	RepoURL         string          `gorm:"type:varchar(255)"`
 	StartDate       *time.Time      `gorm:"type:date"`
 	EndDate         *time.Time      `gorm:"type:date"`
 	IsFeatured      bool            `gorm:"default:false"`
 	ExperienceID    *uuid.UUID      `gorm:"type:uuid;default:null"`
+	VisibleFrom     *time.Time      `gorm:"index"` // hidden from the public before this time
+	VisibleUntil    *time.Time      `gorm:"index"` // hidden from the public from this time on
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	Skills          []*skills.Skill `gorm:"many2many:project_skills;"`
@@ -29,3 +37,25 @@ type Project struct {
 func (Project) TableName() string {
 	return "projects"
 }
+
+// IsVisible reports whether now falls inside the project's visibility window.
+func (p *Project) IsVisible(now time.Time) bool {
+	if p.VisibleFrom != nil && p.VisibleFrom.After(now) {
+		return false
+	}
+	return p.VisibleUntil == nil || p.VisibleUntil.After(now)
+}
+
+// Render caches the HTML, table of contents and reading statistics of the
+// project's markdown.
+func (p *Project) Render() error {
+	result, err := markdown.Render(p.ContentMarkdown)
+	if err != nil {
+		return err
+	}
+	p.ContentHTML = result.HTML
+	p.TableOfContents = result.TOC
+	p.WordCount = result.WordCount
+	p.ReadingTime = result.ReadingTime
+	return nil
+}
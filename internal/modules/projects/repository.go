@@ -2,8 +2,11 @@ package projects
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"gorm.io/gorm"
 )
 
@@ -12,8 +15,10 @@ type Repository interface {
 	Update(project *Project) error
 	Delete(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*Project, error)
-	FindAll(limit, offset int) ([]Project, error)
-	Count() (int64, error)
+	FindBySlug(slug string) (*Project, error)
+	FindAll(visibleOnly bool, limit, offset int) ([]Project, error)
+	Count(visibleOnly bool) (int64, error)
+	FindScheduled(now time.Time) ([]Project, error)
 }
 
 type repository struct {
@@ -25,18 +30,36 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r *repository) Create(project *Project) error {
-	return r.db.Create(project).Error
+	if err := r.db.Create(project).Error; err != nil {
+		return slugConflict(err)
+	}
+	return search.Index(r.db, "projects", project.ID)
 }
 
 func (r *repository) Update(project *Project) error {
 	if err := r.db.Model(project).Association("Skills").Replace(project.Skills); err != nil {
 		return err
 	}
-	return r.db.Save(project).Error
+	if err := r.db.Save(project).Error; err != nil {
+		return slugConflict(err)
+	}
+	return search.Index(r.db, "projects", project.ID)
+}
+
+// slugConflict reports a violated unique slug, the only unique column
+// written here, as taken: another project got the slug after it was checked.
+func slugConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return slugs.ErrSlugTaken
+	}
+	return err
 }
 
 func (r *repository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&Project{}, "id = ?", id).Error
+	if err := r.db.Delete(&Project{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	return search.Unindex(r.db, "projects", id)
 }
 
 func (r *repository) FindByID(id uuid.UUID) (*Project, error) {
@@ -51,9 +74,29 @@ func (r *repository) FindByID(id uuid.UUID) (*Project, error) {
 	return &project, nil
 }
 
-func (r *repository) FindAll(limit, offset int) ([]Project, error) {
+func (r *repository) FindBySlug(slug string) (*Project, error) {
+	var project Project
+	err := r.db.Preload("Skills").Preload("Images").First(&project, "slug = ?", slug).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// visible limits a query to projects whose visibility window includes now.
+func visible(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("(visible_from IS NULL OR visible_from <= ?) AND (visible_until IS NULL OR visible_until > ?)", now, now)
+}
+
+func (r *repository) FindAll(visibleOnly bool, limit, offset int) ([]Project, error) {
 	var projects []Project
 	query := r.db.Preload("Skills").Preload("Images").Order("start_date DESC")
+	if visibleOnly {
+		query = visible(query, time.Now())
+	}
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -61,8 +104,47 @@ func (r *repository) FindAll(limit, offset int) ([]Project, error) {
 	return projects, err
 }
 
-func (r *repository) Count() (int64, error) {
+func (r *repository) Count(visibleOnly bool) (int64, error) {
 	var count int64
-	err := r.db.Model(&Project{}).Count(&count).Error
+	query := r.db.Model(&Project{})
+	if visibleOnly {
+		query = visible(query, time.Now())
+	}
+	err := query.Count(&count).Error
 	return count, err
 }
+
+// FindScheduled returns projects that will appear or disappear after now.
+func (r *repository) FindScheduled(now time.Time) ([]Project, error) {
+	var projects []Project
+	err := r.db.Where("visible_from > ? OR visible_until > ?", now, now).Find(&projects).Error
+	return projects, err
+}
+
+// RenderMissing stores the rendered content of projects saved before it was
+// cached on save, and indexes them again, so the HTML, table of contents and
+// search snippets no longer depend on the raw markdown. The migration runs it
+// at startup; once every row has been rendered it finds nothing to do.
+func RenderMissing(db *gorm.DB) error {
+	var projects []Project
+	return db.Select("id", "content_markdown").
+		Where("COALESCE(content_html, '') = '' AND COALESCE(content_markdown, '') <> ''").
+		FindInBatches(&projects, 100, func(tx *gorm.DB, batch int) error {
+			for i := range projects {
+				project := &projects[i]
+				if err := project.Render(); err != nil {
+					return err
+				}
+				err := db.Model(project).
+					Select("content_html", "table_of_contents", "word_count", "reading_time").
+					UpdateColumns(project).Error
+				if err != nil {
+					return err
+				}
+				if err := search.Index(db, "projects", project.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
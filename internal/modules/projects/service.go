@@ -2,38 +2,167 @@ package projects
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gosimple/slug"
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/indexnow"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/modules/images"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
 	"github.com/prakoso-id/personal-backend/internal/modules/skills"
 	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
 )
 
 type Service interface {
-	Create(req *CreateProjectRequest) (*Project, error)
-	Update(id uuid.UUID, req *UpdateProjectRequest) (*Project, error)
-	Delete(id uuid.UUID) error
+	Create(actor audit.Actor, req *CreateProjectRequest) (*Project, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdateProjectRequest) (*Project, error)
+	Delete(actor audit.Actor, id uuid.UUID) error
 	GetByID(id uuid.UUID) (*Project, error)
+	GetBySlug(slug string) (*Project, error)
+	ResolveSlug(oldSlug string) (string, error)
 	GetAll() ([]Project, error)
 	GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error)
+	GetScheduled(now time.Time) ([]Project, error)
+	RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Project, error)
+}
+
+var (
+	// ErrInvalidSchedule is returned when a visibility window ends before it
+	// starts.
+	ErrInvalidSchedule = errors.New("visible_until must be after visible_from")
+	ErrProjectNotFound = errors.New("project not found")
+)
+
+func validateWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return ErrInvalidSchedule
+	}
+	return nil
 }
 
 type service struct {
 	repo       Repository
 	imagesRepo images.Repository
+	audit      audit.Service
+	revisions  revisions.Service
+	slugs      slugs.Service
+	site       config.SiteConfig
+	indexNow   indexnow.Notifier
 }
 
-func NewService(repo Repository, imagesRepo images.Repository) Service {
+func NewService(repo Repository, imagesRepo images.Repository, auditService audit.Service, revisionService revisions.Service, slugService slugs.Service, site config.SiteConfig, notifier indexnow.Notifier) Service {
 	return &service{
 		repo:       repo,
 		imagesRepo: imagesRepo,
+		audit:      auditService,
+		revisions:  revisionService,
+		slugs:      slugService,
+		site:       site,
+		indexNow:   notifier,
+	}
+}
+
+// projectRevision is the part of a project kept in its revisions and put
+// back when one is restored. The visibility window and images are not
+// versioned.
+type projectRevision struct {
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	SlugPinned      *bool    `json:"slug_pinned,omitempty"` // nil in revisions recorded before it was kept
+	Description     string   `json:"description"`
+	ContentMarkdown string   `json:"content_markdown"`
+	DemoURL         string   `json:"demo_url"`
+	RepoURL         string   `json:"repo_url"`
+	StartDate       string   `json:"start_date"` // YYYY-MM-DD
+	EndDate         string   `json:"end_date"`   // YYYY-MM-DD
+	IsFeatured      bool     `json:"is_featured"`
+	ExperienceID    *string  `json:"experience_id"`
+	SkillIDs        []string `json:"skill_ids"`
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func (p *Project) revisionState() projectRevision {
+	skillIDs := make([]string, 0, len(p.Skills))
+	for _, skill := range p.Skills {
+		skillIDs = append(skillIDs, skill.ID.String())
 	}
+	sort.Strings(skillIDs)
+
+	slugPinned := p.SlugPinned
+	state := projectRevision{
+		Title:           p.Title,
+		Slug:            p.Slug,
+		SlugPinned:      &slugPinned,
+		Description:     p.Description,
+		ContentMarkdown: p.ContentMarkdown,
+		DemoURL:         p.DemoURL,
+		RepoURL:         p.RepoURL,
+		StartDate:       formatDate(p.StartDate),
+		EndDate:         formatDate(p.EndDate),
+		IsFeatured:      p.IsFeatured,
+		SkillIDs:        skillIDs,
+	}
+	if p.ExperienceID != nil {
+		experienceID := p.ExperienceID.String()
+		state.ExperienceID = &experienceID
+	}
+	return state
+}
+
+// notifyChanged tells search engines about a change to the public page of
+// a project: one visible before or after the change. A new slug may move
+// the page, so the old URL is submitted as well.
+func (s *service) notifyChanged(before, after *Project) {
+	now := time.Now()
+	var urls []string
+	if before != nil && before.IsVisible(now) {
+		urls = append(urls, s.site.ProjectURL(before.ID.String(), before.Slug))
+	}
+	if after != nil && after.IsVisible(now) {
+		urls = append(urls, s.site.ProjectURL(after.ID.String(), after.Slug))
+	}
+	s.indexNow.Notify(urls...)
+}
+
+// assignSlug sets the slug of project. A custom slug must be valid and unused;
+// it is pinned, so it is kept when the title changes, until an empty custom
+// slug unpins it again. Otherwise a free slug is generated from the title.
+func (s *service) assignSlug(project *Project, custom *string) error {
+	if custom != nil {
+		if *custom != "" {
+			if err := s.slugs.Check(slugs.EntityProject, project.ID, *custom); err != nil {
+				return err
+			}
+			project.Slug = *custom
+			project.SlugPinned = true
+			return nil
+		}
+		project.SlugPinned = false
+	}
+	if project.SlugPinned {
+		return nil
+	}
+
+	generated, err := s.slugs.Generate(slugs.EntityProject, project.ID, project.Title, project.Slug)
+	if err != nil {
+		return err
+	}
+	project.Slug = generated
+	return nil
 }
 
 type CreateProjectRequest struct {
 	Title           string                     `json:"title"`
+	Slug            *string                    `json:"slug"` // custom slug, pinned against title changes
 	Description     string                     `json:"description"`
 	ContentMarkdown string                     `json:"content_markdown"`
 	DemoURL         string                     `json:"demo_url"`
@@ -41,6 +170,8 @@ type CreateProjectRequest struct {
 	StartDate       string                     `json:"start_date"` // YYYY-MM-DD
 	EndDate         string                     `json:"end_date"`   // YYYY-MM-DD
 	IsFeatured      bool                       `json:"is_featured"`
+	VisibleFrom     *time.Time                 `json:"visible_from"`  // hide before this time
+	VisibleUntil    *time.Time                 `json:"visible_until"` // hide from this time on
 	ExperienceID    *string                    `json:"experience_id"` // UUID or null
 	SkillIDs        []string                   `json:"skill_ids"` // UUIDs
 	Images          []images.ImageUploadResult `json:"images"`
@@ -48,6 +179,7 @@ type CreateProjectRequest struct {
 
 type UpdateProjectRequest struct {
 	Title           string                     `json:"title"`
+	Slug            *string                    `json:"slug"` // custom slug, pinned; "" unpins it, omitted keeps it
 	Description     string                     `json:"description"`
 	ContentMarkdown string                     `json:"content_markdown"`
 	DemoURL         string                     `json:"demo_url"`
@@ -55,6 +187,8 @@ type UpdateProjectRequest struct {
 	StartDate       string                     `json:"start_date"`
 	EndDate         string                     `json:"end_date"`
 	IsFeatured      bool                       `json:"is_featured"`
+	VisibleFrom     *time.Time                 `json:"visible_from"`  // hide before this time
+	VisibleUntil    *time.Time                 `json:"visible_until"` // hide from this time on
 	ExperienceID    *string                    `json:"experience_id"`
 	SkillIDs        []string                   `json:"skill_ids"`
 	Images          []images.ImageUploadResult `json:"images"`
@@ -71,10 +205,13 @@ func parseDate(dateStr string) *time.Time {
 	return &t
 }
 
-func (s *service) Create(req *CreateProjectRequest) (*Project, error) {
+func (s *service) Create(actor audit.Actor, req *CreateProjectRequest) (*Project, error) {
+	if err := validateWindow(req.VisibleFrom, req.VisibleUntil); err != nil {
+		return nil, err
+	}
+
 	project := &Project{
 		Title:           req.Title,
-		Slug:            slug.Make(req.Title),
 		Description:     req.Description,
 		ContentMarkdown: req.ContentMarkdown,
 		DemoURL:         req.DemoURL,
@@ -82,6 +219,11 @@ func (s *service) Create(req *CreateProjectRequest) (*Project, error) {
 		StartDate:       parseDate(req.StartDate),
 		EndDate:         parseDate(req.EndDate),
 		IsFeatured:      req.IsFeatured,
+		VisibleFrom:     req.VisibleFrom,
+		VisibleUntil:    req.VisibleUntil,
+	}
+	if err := s.assignSlug(project, req.Slug); err != nil {
+		return nil, err
 	}
 
 	if req.ExperienceID != nil && *req.ExperienceID != "" {
@@ -100,6 +242,10 @@ func (s *service) Create(req *CreateProjectRequest) (*Project, error) {
 	}
 	project.Skills = projectSkills
 
+	if err := project.Render(); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Create(project); err != nil {
 		return nil, err
 	}
@@ -117,22 +263,29 @@ func (s *service) Create(req *CreateProjectRequest) (*Project, error) {
 		_ = s.imagesRepo.Create(image)
 	}
 
+	s.audit.Record(actor, audit.ActionCreate, "project", project.ID.String(), nil, project)
+	s.revisions.Record(actor, revisions.EntityProject, project.ID, nil, project.revisionState())
+	s.slugs.Record(slugs.EntityProject, project.ID, "", project.Slug)
+	s.notifyChanged(nil, project)
+
 	return project, nil
 }
 
-func (s *service) Update(id uuid.UUID, req *UpdateProjectRequest) (*Project, error) {
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdateProjectRequest) (*Project, error) {
+	if err := validateWindow(req.VisibleFrom, req.VisibleUntil); err != nil {
+		return nil, err
+	}
+
 	project, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if project == nil {
-		return nil, errors.New("project not found")
+		return nil, ErrProjectNotFound
 	}
+	before := *project
 
 	project.Title = req.Title
-	if req.Title != "" {
-		project.Slug = slug.Make(req.Title)
-	}
 	project.Description = req.Description
 	project.ContentMarkdown = req.ContentMarkdown
 	project.DemoURL = req.DemoURL
@@ -140,6 +293,11 @@ func (s *service) Update(id uuid.UUID, req *UpdateProjectRequest) (*Project, err
 	project.StartDate = parseDate(req.StartDate)
 	project.EndDate = parseDate(req.EndDate)
 	project.IsFeatured = req.IsFeatured
+	project.VisibleFrom = req.VisibleFrom
+	project.VisibleUntil = req.VisibleUntil
+	if err := s.assignSlug(project, req.Slug); err != nil {
+		return nil, err
+	}
 
 	if req.ExperienceID != nil {
 		if *req.ExperienceID == "" {
@@ -161,6 +319,10 @@ func (s *service) Update(id uuid.UUID, req *UpdateProjectRequest) (*Project, err
 	}
 	project.Skills = projectSkills
 
+	if err := project.Render(); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Update(project); err != nil {
 		return nil, err
 	}
@@ -178,19 +340,57 @@ func (s *service) Update(id uuid.UUID, req *UpdateProjectRequest) (*Project, err
 		_ = s.imagesRepo.Create(image)
 	}
 
+	s.audit.Record(actor, audit.ActionUpdate, "project", project.ID.String(), before, project)
+	s.revisions.Record(actor, revisions.EntityProject, project.ID, before.revisionState(), project.revisionState())
+	s.slugs.Record(slugs.EntityProject, project.ID, before.Slug, project.Slug)
+	s.notifyChanged(&before, project)
+
 	return project, nil
 }
 
-func (s *service) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *service) Delete(actor audit.Actor, id uuid.UUID) error {
+	project, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	if project != nil {
+		s.audit.Record(actor, audit.ActionDelete, "project", project.ID.String(), project, nil)
+		s.notifyChanged(project, nil)
+	}
+	s.revisions.DeleteDrafts(revisions.EntityProject, id)
+	s.slugs.Forget(slugs.EntityProject, id)
+	return nil
 }
 
 func (s *service) GetByID(id uuid.UUID) (*Project, error) {
 	return s.repo.FindByID(id)
 }
 
+func (s *service) GetBySlug(slug string) (*Project, error) {
+	return s.repo.FindBySlug(slug)
+}
+
+// ResolveSlug returns the current slug of the visible project that used to
+// be at oldSlug, or an empty string when there is none.
+func (s *service) ResolveSlug(oldSlug string) (string, error) {
+	id, err := s.slugs.Resolve(slugs.EntityProject, oldSlug)
+	if err != nil || id == nil {
+		return "", err
+	}
+	project, err := s.repo.FindByID(*id)
+	if err != nil || project == nil || !project.IsVisible(time.Now()) {
+		return "", err
+	}
+	return project.Slug, nil
+}
+
 func (s *service) GetAll() ([]Project, error) {
-	return s.repo.FindAll(0, 0)
+	return s.repo.FindAll(true, 0, 0)
 }
 
 func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error) {
@@ -199,12 +399,12 @@ func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, e
 		Limit: limit,
 	}
 
-	projects, err := s.repo.FindAll(p.Limit, p.Offset())
+	projects, err := s.repo.FindAll(false, p.Limit, p.Offset())
 	if err != nil {
 		return nil, err
 	}
 
-	total, err := s.repo.Count()
+	total, err := s.repo.Count(false)
 	if err != nil {
 		return nil, err
 	}
@@ -212,3 +412,83 @@ func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, e
 	res := pagination.NewResponse(projects, total, p)
 	return &res, nil
 }
+
+// GetScheduled returns projects that will appear or disappear after now.
+func (s *service) GetScheduled(now time.Time) ([]Project, error) {
+	return s.repo.FindScheduled(now)
+}
+
+// RestoreRevision puts the fields saved in a revision back into the project
+// and records the result as a new revision.
+func (s *service) RestoreRevision(actor audit.Actor, id uuid.UUID, number int) (*Project, error) {
+	project, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	revision, err := s.revisions.Get(revisions.EntityProject, id, number)
+	if err != nil {
+		return nil, err
+	}
+	var state projectRevision
+	if err := revisions.Decode(revision, &state); err != nil {
+		return nil, err
+	}
+	before := *project
+
+	project.Title = state.Title
+	project.Slug = state.Slug
+	if state.SlugPinned != nil {
+		project.SlugPinned = *state.SlugPinned
+	}
+	// Another project may have taken the slug since; the generated one
+	// follows the title again
+	if err := s.slugs.Check(slugs.EntityProject, project.ID, project.Slug); err != nil {
+		if project.Slug, err = s.slugs.Generate(slugs.EntityProject, project.ID, project.Title, ""); err != nil {
+			return nil, err
+		}
+		project.SlugPinned = false
+	}
+	project.Description = state.Description
+	project.ContentMarkdown = state.ContentMarkdown
+	project.DemoURL = state.DemoURL
+	project.RepoURL = state.RepoURL
+	project.StartDate = parseDate(state.StartDate)
+	project.EndDate = parseDate(state.EndDate)
+	project.IsFeatured = state.IsFeatured
+
+	project.ExperienceID = nil
+	if state.ExperienceID != nil {
+		experienceID, err := uuid.Parse(*state.ExperienceID)
+		if err == nil {
+			project.ExperienceID = &experienceID
+		}
+	}
+
+	var projectSkills []*skills.Skill
+	for _, idStr := range state.SkillIDs {
+		skillID, err := uuid.Parse(idStr)
+		if err == nil {
+			projectSkills = append(projectSkills, &skills.Skill{ID: skillID})
+		}
+	}
+	project.Skills = projectSkills
+
+	if err := project.Render(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(project); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionRestore, "project", project.ID.String(), before, project)
+	s.revisions.RecordRestore(actor, revisions.EntityProject, project.ID, project.revisionState(), revision.Number)
+	s.slugs.Record(slugs.EntityProject, project.ID, before.Slug, project.Slug)
+	s.notifyChanged(&before, project)
+
+	return project, nil
+}
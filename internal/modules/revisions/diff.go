@@ -0,0 +1,106 @@
+package revisions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+func isMultiline(from, to string) bool {
+	return strings.Contains(from, "\n") || strings.Contains(to, "\n")
+}
+
+// unifiedDiff compares two texts line by line and formats the changes like
+// `diff -u`, without file headers.
+func unifiedDiff(from, to string) string {
+	dmp := diffmatchpatch.New()
+	fromChars, toChars, lines := dmp.DiffLinesToChars(from, to)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lines)
+
+	var all []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, line := range splitLines(d.Text) {
+			all = append(all, diffLine{op: op, text: line})
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(all); {
+		for i < len(all) && all[i].op == ' ' {
+			i++
+		}
+		if i == len(all) {
+			break
+		}
+
+		// A hunk runs until the next change is further away than twice the context
+		last := i
+		for j := i; j < len(all); j++ {
+			if all[j].op != ' ' {
+				last = j
+			} else if j-last > 2*diffContext {
+				break
+			}
+		}
+		start := max(i-diffContext, 0)
+		stop := min(last+diffContext+1, len(all))
+
+		oldStart, newStart := lineCounts(all[:start])
+		oldLen, newLen := lineCounts(all[start:stop])
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+		for _, line := range all[start:stop] {
+			b.WriteByte(line.op)
+			b.WriteString(line.text)
+			b.WriteByte('\n')
+		}
+		i = stop
+	}
+	return b.String()
+}
+
+// lineCounts returns how many of the lines belong to the old and new text.
+func lineCounts(lines []diffLine) (oldCount, newCount int) {
+	for _, line := range lines {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+	return oldCount, newCount
+}
+
+func hunkRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
+
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
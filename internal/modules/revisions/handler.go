@@ -0,0 +1,359 @@
+package revisions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+// maxDraftSize caps the body of an autosave request.
+const maxDraftSize = 1 << 20 // 1 MiB
+
+// Handler serves the revision and autosave routes of every entity type.
+// Each method returns the handler for one type; the entity ID is taken from
+// the :id path parameter.
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// toRevisionResponse maps a revision to the API representation. Listings
+// leave out the snapshot.
+func toRevisionResponse(revision *Revision, withSnapshot bool) gin.H {
+	res := gin.H{
+		"id":            revision.ID,
+		"entity_type":   revision.EntityType,
+		"entity_id":     revision.EntityID,
+		"number":        revision.Number,
+		"author_id":     revision.AuthorID,
+		"api_key_id":    revision.APIKeyID,
+		"restored_from": revision.RestoredFrom,
+		"created_at":    revision.CreatedAt,
+	}
+	if withSnapshot {
+		res["snapshot"] = revision.Snapshot
+	}
+	return res
+}
+
+func toDraftResponse(draft *Draft) gin.H {
+	return gin.H{
+		"entity_type":   draft.EntityType,
+		"entity_id":     draft.EntityID,
+		"base_revision": draft.BaseRevision,
+		"content":       draft.Content,
+		"updated_at":    draft.UpdatedAt,
+	}
+}
+
+func toFieldDiffResponse(diff *FieldDiff) gin.H {
+	res := gin.H{
+		"field": diff.Field,
+		"from":  diff.From,
+		"to":    diff.To,
+	}
+	if diff.Diff != "" {
+		res["diff"] = diff.Diff
+	}
+	return res
+}
+
+func entityID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseNumber reads a revision number; an empty value means 0.
+func parseNumber(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, errors.New("revision number must be a positive integer")
+	}
+	return number, nil
+}
+
+func writeError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, ErrRevisionNotFound), errors.Is(err, ErrEntityNotFound), errors.Is(err, ErrDraftNotFound):
+		response.Error(c, http.StatusNotFound, message, err.Error())
+	case errors.Is(err, ErrUserRequired):
+		response.Error(c, http.StatusForbidden, message, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// List godoc
+// @Summary      Admin - List Revisions
+// @Description  Retrieve the saved revisions of a post, project, experience or profile, newest first
+// @Tags         Admin - Revisions
+// @Produce      json
+// @Param        id     path     string  true   "Entity ID"
+// @Param        page   query    int     false  "Page number" default(1)
+// @Param        limit  query    int     false  "Items per page" default(10)
+// @Security     BearerAuth
+// @Success      200  {object}  pagination.PaginatedResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/posts/{id}/revisions [get]
+// @Router       /admin/projects/{id}/revisions [get]
+// @Router       /admin/experiences/{id}/revisions [get]
+// @Router       /admin/profile/{id}/revisions [get]
+func (h *Handler) List(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+
+		p := pagination.FromContext(c)
+		result, err := h.service.List(entityType, id, p.Page, p.Limit)
+		if err != nil {
+			writeError(c, "Failed to fetch revisions", err)
+			return
+		}
+
+		revisions := result.Data.([]Revision)
+		data := make([]gin.H, 0, len(revisions))
+		for i := range revisions {
+			data = append(data, toRevisionResponse(&revisions[i], false))
+		}
+		result.Data = data
+
+		response.Success(c, http.StatusOK, "Revisions fetched successfully", result)
+	}
+}
+
+// Get godoc
+// @Summary      Admin - Get Revision
+// @Description  Retrieve one revision including the saved fields
+// @Tags         Admin - Revisions
+// @Produce      json
+// @Param        id      path     string  true  "Entity ID"
+// @Param        number  path     int     true  "Revision number"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/posts/{id}/revisions/{number} [get]
+// @Router       /admin/projects/{id}/revisions/{number} [get]
+// @Router       /admin/experiences/{id}/revisions/{number} [get]
+// @Router       /admin/profile/{id}/revisions/{number} [get]
+func (h *Handler) Get(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+		number, err := parseNumber(c.Param("number"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid revision number", err.Error())
+			return
+		}
+
+		revision, err := h.service.Get(entityType, id, number)
+		if err != nil {
+			writeError(c, "Failed to fetch revision", err)
+			return
+		}
+		response.Success(c, http.StatusOK, "Revision fetched successfully", toRevisionResponse(revision, true))
+	}
+}
+
+// Diff godoc
+// @Summary      Admin - Diff Revisions
+// @Description  Compare two revisions field by field. Multi-line text such as markdown also gets a unified line diff. Without "to" the latest revision is used, without "from" the one before "to".
+// @Tags         Admin - Revisions
+// @Produce      json
+// @Param        id    path     string  true   "Entity ID"
+// @Param        from  query    int     false  "Older revision number"
+// @Param        to    query    int     false  "Newer revision number"
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/posts/{id}/revisions/diff [get]
+// @Router       /admin/projects/{id}/revisions/diff [get]
+// @Router       /admin/experiences/{id}/revisions/diff [get]
+// @Router       /admin/profile/{id}/revisions/diff [get]
+func (h *Handler) Diff(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+		from, err := parseNumber(c.Query("from"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid from", err.Error())
+			return
+		}
+		to, err := parseNumber(c.Query("to"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid to", err.Error())
+			return
+		}
+
+		diffs, err := h.service.Diff(entityType, id, from, to)
+		if err != nil {
+			writeError(c, "Failed to compare revisions", err)
+			return
+		}
+
+		data := make([]gin.H, 0, len(diffs))
+		for i := range diffs {
+			data = append(data, toFieldDiffResponse(&diffs[i]))
+		}
+		response.Success(c, http.StatusOK, "Revisions compared successfully", data)
+	}
+}
+
+// GetDraft godoc
+// @Summary      Admin - Get Autosaved Draft
+// @Description  Retrieve the caller's autosaved draft. base_revision is the latest revision when it was saved; if the entity has newer revisions, the draft is stale.
+// @Tags         Admin - Revisions
+// @Produce      json
+// @Param        id  path  string  true  "Entity ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/posts/{id}/autosave [get]
+// @Router       /admin/projects/{id}/autosave [get]
+// @Router       /admin/experiences/{id}/autosave [get]
+// @Router       /admin/profile/{id}/autosave [get]
+func (h *Handler) GetDraft(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+
+		draft, err := h.service.GetDraft(audit.ActorFromContext(c), entityType, id)
+		if err != nil {
+			writeError(c, "Failed to fetch draft", err)
+			return
+		}
+		response.Success(c, http.StatusOK, "Draft fetched successfully", toDraftResponse(draft))
+	}
+}
+
+// SaveDraft godoc
+// @Summary      Admin - Autosave Draft
+// @Description  Store unsaved editor content as the caller's draft, replacing the previous one. The content is a JSON object with the fields of the entity's update request, all optional; unknown fields and wrong types are rejected, and bodies over 1 MiB answer 413. Drafts do not create revisions and are discarded when the caller saves the entity.
+// @Tags         Admin - Revisions
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                  true  "Entity ID"
+// @Param        request  body  map[string]interface{}  true  "Draft content"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Router       /admin/posts/{id}/autosave [put]
+// @Router       /admin/projects/{id}/autosave [put]
+// @Router       /admin/experiences/{id}/autosave [put]
+// @Router       /admin/profile/{id}/autosave [put]
+//
+// request is the update request struct of the entity type; the content must
+// decode into it. It is stored as sent, so omitted fields stay omitted.
+func (h *Handler) SaveDraft(entityType string, request interface{}) gin.HandlerFunc {
+	requestType := reflect.TypeOf(request)
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxDraftSize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Error(c, http.StatusRequestEntityTooLarge, "Draft too large", err.Error())
+				return
+			}
+			response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+
+		if err := validateDraft(body, requestType); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+		var content map[string]interface{}
+		if err := json.Unmarshal(body, &content); err != nil || content == nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request", "draft must be a JSON object")
+			return
+		}
+
+		draft, err := h.service.SaveDraft(audit.ActorFromContext(c), entityType, id, content)
+		if err != nil {
+			writeError(c, "Failed to save draft", err)
+			return
+		}
+		response.Success(c, http.StatusOK, "Draft saved successfully", toDraftResponse(draft))
+	}
+}
+
+// DeleteDraft godoc
+// @Summary      Admin - Discard Autosaved Draft
+// @Description  Delete the caller's autosaved draft
+// @Tags         Admin - Revisions
+// @Produce      json
+// @Param        id  path  string  true  "Entity ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Router       /admin/posts/{id}/autosave [delete]
+// @Router       /admin/projects/{id}/autosave [delete]
+// @Router       /admin/experiences/{id}/autosave [delete]
+// @Router       /admin/profile/{id}/autosave [delete]
+func (h *Handler) DeleteDraft(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := entityID(c)
+		if !ok {
+			return
+		}
+
+		if err := h.service.DeleteDraft(audit.ActorFromContext(c), entityType, id); err != nil {
+			writeError(c, "Failed to discard draft", err)
+			return
+		}
+		response.Success(c, http.StatusOK, "Draft discarded successfully", nil)
+	}
+}
+
+// validateDraft checks that body is a JSON object that decodes into a value
+// of requestType without unknown fields and passes its binding rules.
+func validateDraft(body []byte, requestType reflect.Type) error {
+	request := reflect.New(requestType).Interface()
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the draft object")
+	}
+	return binding.Validator.ValidateStruct(request)
+}
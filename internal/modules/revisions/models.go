@@ -0,0 +1,68 @@
+package revisions
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types that keep revisions
+const (
+	EntityPost       = "post"
+	EntityProject    = "project"
+	EntityExperience = "experience"
+	EntityProfile    = "profile"
+)
+
+// entityTables maps each entity type to the table holding its rows.
+var entityTables = map[string]string{
+	EntityPost:       "posts",
+	EntityProject:    "projects",
+	EntityExperience: "experiences",
+	EntityProfile:    "profiles",
+}
+
+// Revision is an immutable copy of the editable fields of an entity, taken
+// every time it is saved. Numbers start at 1 for each entity.
+type Revision struct {
+	ID           uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	EntityType   string                 `gorm:"type:varchar(50);not null;uniqueIndex:idx_revisions_entity_number"`
+	EntityID     uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex:idx_revisions_entity_number"`
+	Number       int                    `gorm:"not null;uniqueIndex:idx_revisions_entity_number"`
+	Snapshot     map[string]interface{} `gorm:"type:jsonb;serializer:json;not null"`
+	AuthorID     *uuid.UUID             `gorm:"type:uuid"`
+	APIKeyID     *uuid.UUID             `gorm:"type:uuid"`
+	RestoredFrom *int                   // number of the revision this one restored
+	CreatedAt    time.Time
+}
+
+func (Revision) TableName() string {
+	return "revisions"
+}
+
+// Draft is unsaved editor content kept by autosave. Each author has at most
+// one draft per entity; it is replaced on every autosave and removed when
+// the author saves the entity.
+type Draft struct {
+	ID           uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	EntityType   string                 `gorm:"type:varchar(50);not null;uniqueIndex:idx_revision_drafts_entity_author"`
+	EntityID     uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex:idx_revision_drafts_entity_author"`
+	AuthorID     uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex:idx_revision_drafts_entity_author"`
+	BaseRevision int                    `gorm:"not null;default:0"` // latest revision when the draft was saved
+	Content      map[string]interface{} `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (Draft) TableName() string {
+	return "revision_drafts"
+}
+
+// FieldDiff is one field that differs between two revisions. Multi-line
+// text such as markdown also gets a unified line diff.
+type FieldDiff struct {
+	Field string
+	From  interface{}
+	To    interface{}
+	Diff  string
+}
@@ -0,0 +1,139 @@
+package revisions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Create(revision *Revision) error
+	FindLatest(entityType string, entityID uuid.UUID) (*Revision, error)
+	FindByNumber(entityType string, entityID uuid.UUID, number int) (*Revision, error)
+	FindAll(entityType string, entityID uuid.UUID, limit, offset int) ([]Revision, error)
+	Count(entityType string, entityID uuid.UUID) (int64, error)
+	EntityExists(entityType string, entityID uuid.UUID) (bool, error)
+	FindDraft(entityType string, entityID, authorID uuid.UUID) (*Draft, error)
+	SaveDraft(draft *Draft) error
+	DeleteDraft(entityType string, entityID, authorID uuid.UUID) error
+	DeleteDrafts(entityType string, entityID uuid.UUID) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create numbers the revision after the latest one of its entity. Concurrent
+// saves of the same entity are serialized with an advisory lock.
+func (r *repository) Create(revision *Revision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		lockKey := revision.EntityType + ":" + revision.EntityID.String()
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", lockKey).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		err := tx.Model(&Revision{}).
+			Where("entity_type = ? AND entity_id = ?", revision.EntityType, revision.EntityID).
+			Select("COALESCE(MAX(number), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		revision.Number = latest + 1
+		return tx.Create(revision).Error
+	})
+}
+
+func (r *repository) FindLatest(entityType string, entityID uuid.UUID) (*Revision, error) {
+	var revision Revision
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("number DESC").First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *repository) FindByNumber(entityType string, entityID uuid.UUID, number int) (*Revision, error) {
+	var revision Revision
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND number = ?", entityType, entityID, number).
+		First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *repository) FindAll(entityType string, entityID uuid.UUID, limit, offset int) ([]Revision, error) {
+	var revisions []Revision
+	query := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("number DESC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	err := query.Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *repository) Count(entityType string, entityID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&Revision{}).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) EntityExists(entityType string, entityID uuid.UUID) (bool, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return false, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	var count int64
+	err := r.db.Table(table).Where("id = ?", entityID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) FindDraft(entityType string, entityID, authorID uuid.UUID) (*Draft, error) {
+	var draft Draft
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND author_id = ?", entityType, entityID, authorID).
+		First(&draft).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// SaveDraft inserts the draft or replaces the author's existing one.
+func (r *repository) SaveDraft(draft *Draft) error {
+	return r.db.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "author_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"base_revision", "content", "updated_at"}),
+		},
+		clause.Returning{},
+	).Create(draft).Error
+}
+
+func (r *repository) DeleteDraft(entityType string, entityID, authorID uuid.UUID) error {
+	return r.db.Where("entity_type = ? AND entity_id = ? AND author_id = ?", entityType, entityID, authorID).
+		Delete(&Draft{}).Error
+}
+
+func (r *repository) DeleteDrafts(entityType string, entityID uuid.UUID) error {
+	return r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(&Draft{}).Error
+}
@@ -0,0 +1,286 @@
+package revisions
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrEntityNotFound   = errors.New("entity not found")
+	ErrDraftNotFound    = errors.New("draft not found")
+	ErrUserRequired     = errors.New("autosave requires a user")
+)
+
+type Service interface {
+	Record(actor audit.Actor, entityType string, entityID uuid.UUID, before, after interface{})
+	RecordRestore(actor audit.Actor, entityType string, entityID uuid.UUID, after interface{}, from int)
+	List(entityType string, entityID uuid.UUID, page, limit int) (*pagination.PaginatedResponse, error)
+	Get(entityType string, entityID uuid.UUID, number int) (*Revision, error)
+	Diff(entityType string, entityID uuid.UUID, from, to int) ([]FieldDiff, error)
+	GetDraft(actor audit.Actor, entityType string, entityID uuid.UUID) (*Draft, error)
+	SaveDraft(actor audit.Actor, entityType string, entityID uuid.UUID, content map[string]interface{}) (*Draft, error)
+	DeleteDraft(actor audit.Actor, entityType string, entityID uuid.UUID) error
+	DeleteDrafts(entityType string, entityID uuid.UUID)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Decode copies the snapshot of a revision into the state struct of its
+// entity, which must use the JSON field names it was recorded with.
+func Decode(revision *Revision, state interface{}) error {
+	data, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, state)
+}
+
+// toSnapshot converts a state struct to the generic form stored in a
+// revision.
+func toSnapshot(state interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Record stores after as a new revision of the entity, unless it equals the
+// latest one, and discards the actor's autosaved draft. An entity saved for
+// the first time since revisions were introduced gets before as its first
+// revision, so the change can still be undone. Failures are logged and do
+// not fail the save.
+func (s *service) Record(actor audit.Actor, entityType string, entityID uuid.UUID, before, after interface{}) {
+	if err := s.record(actor, entityType, entityID, before, after, nil); err != nil {
+		log.Printf("failed to record %s revision: %v", entityType, err)
+	}
+}
+
+// RecordRestore stores after as a new revision noting the revision it was
+// restored from. Restoring never rewrites history.
+func (s *service) RecordRestore(actor audit.Actor, entityType string, entityID uuid.UUID, after interface{}, from int) {
+	if err := s.record(actor, entityType, entityID, nil, after, &from); err != nil {
+		log.Printf("failed to record %s revision: %v", entityType, err)
+	}
+}
+
+func (s *service) record(actor audit.Actor, entityType string, entityID uuid.UUID, before, after interface{}, restoredFrom *int) error {
+	if actor.UserID != uuid.Nil {
+		if err := s.repo.DeleteDraft(entityType, entityID, actor.UserID); err != nil {
+			return err
+		}
+	}
+
+	snapshot, err := toSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	latest, err := s.repo.FindLatest(entityType, entityID)
+	if err != nil {
+		return err
+	}
+
+	if latest == nil && before != nil {
+		baseline, err := toSnapshot(before)
+		if err != nil {
+			return err
+		}
+		latest = &Revision{EntityType: entityType, EntityID: entityID, Snapshot: baseline}
+		if err := s.repo.Create(latest); err != nil {
+			return err
+		}
+	}
+
+	if latest != nil && restoredFrom == nil && reflect.DeepEqual(latest.Snapshot, snapshot) {
+		return nil
+	}
+
+	revision := &Revision{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Snapshot:     snapshot,
+		APIKeyID:     actor.APIKeyID,
+		RestoredFrom: restoredFrom,
+	}
+	if actor.UserID != uuid.Nil {
+		authorID := actor.UserID
+		revision.AuthorID = &authorID
+	}
+	return s.repo.Create(revision)
+}
+
+func (s *service) List(entityType string, entityID uuid.UUID, page, limit int) (*pagination.PaginatedResponse, error) {
+	p := pagination.Pagination{
+		Page:  page,
+		Limit: limit,
+	}
+
+	revisions, err := s.repo.FindAll(entityType, entityID, p.Limit, p.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.Count(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := pagination.NewResponse(revisions, total, p)
+	return &res, nil
+}
+
+// Get returns the revision with the given number, or the latest one when
+// number is 0.
+func (s *service) Get(entityType string, entityID uuid.UUID, number int) (*Revision, error) {
+	var revision *Revision
+	var err error
+	if number == 0 {
+		revision, err = s.repo.FindLatest(entityType, entityID)
+	} else {
+		revision, err = s.repo.FindByNumber(entityType, entityID, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// Diff compares two revisions field by field. A to of 0 means the latest
+// revision and a from of 0 the one before to.
+func (s *service) Diff(entityType string, entityID uuid.UUID, from, to int) ([]FieldDiff, error) {
+	toRevision, err := s.Get(entityType, entityID, to)
+	if err != nil {
+		return nil, err
+	}
+	if from == 0 {
+		from = toRevision.Number - 1
+		if from == 0 {
+			return []FieldDiff{}, nil
+		}
+	}
+	fromRevision, err := s.Get(entityType, entityID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffSnapshots(fromRevision.Snapshot, toRevision.Snapshot), nil
+}
+
+func diffSnapshots(from, to map[string]interface{}) []FieldDiff {
+	fields := make(map[string]bool)
+	for field := range from {
+		fields[field] = true
+	}
+	for field := range to {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	diffs := []FieldDiff{}
+	for _, field := range names {
+		if reflect.DeepEqual(from[field], to[field]) {
+			continue
+		}
+
+		diff := FieldDiff{Field: field, From: from[field], To: to[field]}
+		fromText, fromIsText := from[field].(string)
+		toText, toIsText := to[field].(string)
+		if (fromIsText || from[field] == nil) && (toIsText || to[field] == nil) && isMultiline(fromText, toText) {
+			diff.Diff = unifiedDiff(fromText, toText)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func (s *service) GetDraft(actor audit.Actor, entityType string, entityID uuid.UUID) (*Draft, error) {
+	if actor.UserID == uuid.Nil {
+		return nil, ErrUserRequired
+	}
+
+	draft, err := s.repo.FindDraft(entityType, entityID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+// SaveDraft keeps content as the actor's autosaved draft of an existing
+// entity, replacing the previous draft. Drafts never create revisions.
+func (s *service) SaveDraft(actor audit.Actor, entityType string, entityID uuid.UUID, content map[string]interface{}) (*Draft, error) {
+	if actor.UserID == uuid.Nil {
+		return nil, ErrUserRequired
+	}
+
+	exists, err := s.repo.EntityExists(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrEntityNotFound
+	}
+
+	latest, err := s.repo.FindLatest(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &Draft{
+		EntityType: entityType,
+		EntityID:   entityID,
+		AuthorID:   actor.UserID,
+		Content:    content,
+	}
+	if latest != nil {
+		draft.BaseRevision = latest.Number
+	}
+	if err := s.repo.SaveDraft(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (s *service) DeleteDraft(actor audit.Actor, entityType string, entityID uuid.UUID) error {
+	if actor.UserID == uuid.Nil {
+		return ErrUserRequired
+	}
+	return s.repo.DeleteDraft(entityType, entityID, actor.UserID)
+}
+
+// DeleteDrafts discards every draft of a deleted entity. Its revisions are
+// kept.
+func (s *service) DeleteDrafts(entityType string, entityID uuid.UUID) {
+	if err := s.repo.DeleteDrafts(entityType, entityID); err != nil {
+		log.Printf("failed to delete %s drafts: %v", entityType, err)
+	}
+}
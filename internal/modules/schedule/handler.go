@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+	domain  string // used in iCal event UIDs
+}
+
+func NewHandler(service Service, baseURL string) *Handler {
+	domain := "localhost"
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		domain = u.Hostname()
+	}
+	return &Handler{service: service, domain: domain}
+}
+
+// toItemResponse maps a scheduled item to the API representation.
+func toItemResponse(item *Item) gin.H {
+	return gin.H{
+		"type":   item.Type,
+		"id":     item.ID,
+		"title":  item.Title,
+		"slug":   item.Slug,
+		"action": item.Action,
+		"at":     item.At,
+	}
+}
+
+// GetSchedule godoc
+// @Summary      Admin - Get Publishing Schedule
+// @Description  Lists upcoming scheduled changes, soonest first: posts to publish or unpublish and projects to show or hide
+// @Tags         Admin - Schedule
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/schedule [get]
+func (h *Handler) GetSchedule(c *gin.Context) {
+	items, err := h.service.Upcoming()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch schedule", err.Error())
+		return
+	}
+
+	data := make([]gin.H, 0, len(items))
+	for i := range items {
+		data = append(data, toItemResponse(&items[i]))
+	}
+	response.Success(c, http.StatusOK, "Schedule fetched successfully", data)
+}
+
+// GetScheduleICal godoc
+// @Summary      Admin - Get Publishing Schedule (iCal)
+// @Description  The upcoming scheduled changes as an iCalendar feed for calendar apps. Authenticate with an API key holding posts:read and projects:read.
+// @Tags         Admin - Schedule
+// @Produce      text/calendar
+// @Security     BearerAuth
+// @Success      200  {string}  string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/schedule.ics [get]
+func (h *Handler) GetScheduleICal(c *gin.Context) {
+	items, err := h.service.Upcoming()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch schedule", err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="schedule.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderICal(items, h.domain, time.Now())))
+}
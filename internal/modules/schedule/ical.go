@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	icalLineLength = 75 // octets, longer lines are folded
+)
+
+var icalSummaries = map[string]string{
+	ActionPublish:   "Publish post",
+	ActionUnpublish: "Unpublish post",
+	ActionShow:      "Show project",
+	ActionHide:      "Hide project",
+}
+
+// renderICal writes the items as an RFC 5545 calendar with one event per
+// scheduled change. UIDs stay the same when an item is rescheduled, so
+// calendar clients move the event instead of adding another.
+func renderICal(items []Item, domain string, now time.Time) string {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(foldICalLine(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//personal-backend//Publishing Schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Publishing Schedule")
+	for _, item := range items {
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s-%s@%s", item.Action, item.Type, item.ID, domain)
+		line("DTSTAMP:%s", now.UTC().Format(icalTimeFormat))
+		line("DTSTART:%s", item.At.UTC().Format(icalTimeFormat))
+		line("DTEND:%s", item.At.UTC().Format(icalTimeFormat))
+		line("SUMMARY:%s", escapeICalText(icalSummaries[item.Action]+": "+item.Title))
+		line("DESCRIPTION:%s", escapeICalText("Slug: "+item.Slug))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return b.String()
+}
+
+func escapeICalText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// foldICalLine splits a content line into lines of at most 75 octets,
+// continuing each with a space and never splitting a UTF-8 sequence.
+func foldICalLine(line string) string {
+	if len(line) <= icalLineLength {
+		return line
+	}
+
+	var b strings.Builder
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLength - 1 // the leading space counts
+	}
+	b.WriteString(line)
+	return b.String()
+}
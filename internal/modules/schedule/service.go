@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/projects"
+)
+
+// Scheduled actions
+const (
+	ActionPublish   = "publish"   // post becomes public
+	ActionUnpublish = "unpublish" // post is withdrawn
+	ActionShow      = "show"      // project enters its visibility window
+	ActionHide      = "hide"      // project leaves its visibility window
+)
+
+// Item is one upcoming change of public visibility.
+type Item struct {
+	Type   string // post or project
+	ID     uuid.UUID
+	Title  string
+	Slug   string
+	Action string
+	At     time.Time
+}
+
+type Service interface {
+	Upcoming() ([]Item, error)
+}
+
+type service struct {
+	posts    posts.Service
+	projects projects.Service
+}
+
+func NewService(postService posts.Service, projectService projects.Service) Service {
+	return &service{posts: postService, projects: projectService}
+}
+
+// Upcoming lists pending publications and withdrawals of posts and the
+// visibility windows of projects that have yet to start or end, soonest first.
+func (s *service) Upcoming() ([]Item, error) {
+	now := time.Now()
+
+	scheduledPosts, err := s.posts.GetScheduled()
+	if err != nil {
+		return nil, err
+	}
+	scheduledProjects, err := s.projects.GetScheduled(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, post := range scheduledPosts {
+		if post.PublishAt != nil {
+			items = append(items, Item{"post", post.ID, post.Title, post.Slug, ActionPublish, *post.PublishAt})
+		}
+		if post.UnpublishAt != nil {
+			items = append(items, Item{"post", post.ID, post.Title, post.Slug, ActionUnpublish, *post.UnpublishAt})
+		}
+	}
+	for _, project := range scheduledProjects {
+		if project.VisibleFrom != nil && project.VisibleFrom.After(now) {
+			items = append(items, Item{"project", project.ID, project.Title, project.Slug, ActionShow, *project.VisibleFrom})
+		}
+		if project.VisibleUntil != nil && project.VisibleUntil.After(now) {
+			items = append(items, Item{"project", project.ID, project.Title, project.Slug, ActionHide, *project.VisibleUntil})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].At.Before(items[j].At)
+	})
+	return items, nil
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func toResultResponse(result *Result) gin.H {
+	return gin.H{
+		"type":    result.Type,
+		"id":      result.ID,
+		"title":   result.Title,
+		"slug":    result.Slug,
+		"rank":    result.Rank,
+		"snippet": result.Snippet,
+	}
+}
+
+// Search godoc
+// @Summary      Public - Search
+// @Description  Full-text search across published posts, visible projects and experiences, best matches first. Every word must match, as a prefix, so partial input already finds results. Snippets are HTML-escaped with matches wrapped in <mark>. When nothing matches, "suggestion" may hold a corrected query.
+// @Tags         Public - Search
+// @Produce      json
+// @Param        q      query    string  true   "Search query"
+// @Param        page   query    int     false  "Page number" default(1)
+// @Param        limit  query    int     false  "Items per page" default(10)
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /public/search [get]
+func (h *Handler) Search(c *gin.Context) {
+	p := pagination.FromContext(c)
+	res, err := h.service.Search(c.Query("q"), p.Page, p.Limit)
+	if err != nil {
+		if errors.Is(err, ErrQueryRequired) {
+			response.Error(c, http.StatusBadRequest, "Invalid query", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to search", err.Error())
+		return
+	}
+
+	results := res.Results.Data.([]Result)
+	data := make([]gin.H, 0, len(results))
+	for i := range results {
+		data = append(data, toResultResponse(&results[i]))
+	}
+
+	var suggestion interface{}
+	if res.Suggestion != "" {
+		suggestion = res.Suggestion
+	}
+
+	response.Success(c, http.StatusOK, "Search completed successfully", gin.H{
+		"data":       data,
+		"meta":       res.Results.Meta,
+		"suggestion": suggestion,
+	})
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// contentText turns the cached, sanitized HTML of a row into the plain text
+// readers see. The body is indexed and snippets are cut from this text, so
+// link targets, image paths and markdown syntax neither match nor show up.
+// The sanitizer only writes these entities; &amp; goes last so that an
+// escaped entity stays text.
+const contentText = `replace(replace(replace(replace(replace(
+	regexp_replace(coalesce(content_html, ''), '<[^>]*>', ' ', 'g'),
+	'&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&')`
+
+// documents maps each searchable table to the weighted tsvector its rows are
+// matched against: A for the title, B for the summary or description and C
+// for the body. The 'simple' configuration does no stemming, so prefix
+// queries match the words as written in any language.
+var documents = map[string]string{
+	"posts": `setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(summary, '')), 'B') ||
+		setweight(to_tsvector('simple', ` + contentText + `), 'C')`,
+	"projects": `setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('simple', ` + contentText + `), 'C')`,
+	"experiences": `setweight(to_tsvector('simple', coalesce(position, '') || ' ' || coalesce(company, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(description, '')), 'B')`,
+}
+
+// tables lists the searchable tables in a fixed order.
+var tables = []string{"posts", "projects", "experiences"}
+
+// lexiconSQL adds the distinct words of the search vectors of a table to the
+// lexicon, which "did you mean" suggestions are looked up in. The caller
+// appends the condition selecting the rows.
+func lexiconSQL(table string) string {
+	return "INSERT INTO search_lexicon (table_name, row_id, word) " +
+		"SELECT DISTINCT '" + table + "', t.id, v.lexeme FROM " + table + " t, unnest(t.search_vector) AS v " +
+		"WHERE "
+}
+
+// Index refreshes the search vector and the lexicon words of one row.
+// Repositories call it after every create and update.
+func Index(db *gorm.DB, table string, id uuid.UUID) error {
+	document, ok := documents[table]
+	if !ok {
+		return fmt.Errorf("search: %s is not searchable", table)
+	}
+	if err := db.Exec("UPDATE "+table+" SET search_vector = "+document+" WHERE id = ?", id).Error; err != nil {
+		return err
+	}
+	if err := Unindex(db, table, id); err != nil {
+		return err
+	}
+	return db.Exec(lexiconSQL(table)+"t.id = ?", id).Error
+}
+
+// Unindex removes the lexicon words of one row. Repositories call it after
+// a delete.
+func Unindex(db *gorm.DB, table string, id uuid.UUID) error {
+	return db.Exec("DELETE FROM search_lexicon WHERE table_name = ? AND row_id = ?", table, id).Error
+}
+
+// Migrate adds the search columns, the lexicon and their GIN indexes, which
+// are not part of the GORM models, and indexes rows that have no search
+// vector or lexicon words yet or were indexed from an earlier definition of
+// their document.
+func Migrate(db *gorm.DB) error {
+	// similarity() and % for "did you mean" suggestions
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := db.Exec("ALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS search_vector tsvector").Error; err != nil {
+			return err
+		}
+		if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_" + table + "_search_vector ON " + table + " USING GIN (search_vector)").Error; err != nil {
+			return err
+		}
+	}
+
+	// The words of every row, with a trigram index for similarity lookups.
+	// Suggestions join it with the tables to apply their visibility rules.
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS search_lexicon (
+		table_name VARCHAR(20) NOT NULL,
+		row_id UUID NOT NULL,
+		word TEXT NOT NULL,
+		PRIMARY KEY (table_name, row_id, word)
+	)`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_search_lexicon_word ON search_lexicon USING GIN (word gin_trgm_ops)").Error; err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := resetOutdated(db, table); err != nil {
+			return err
+		}
+	}
+	return Reindex(db, false)
+}
+
+// resetOutdated clears the search vectors and lexicon words of a table when
+// they were built from an earlier definition of its document, so Reindex
+// builds them again. A comment on the search_vector column records the
+// definition in use; it is set last, so a failed reset is repeated.
+func resetOutdated(db *gorm.DB, table string) error {
+	sum := sha256.Sum256([]byte(documents[table]))
+	version := hex.EncodeToString(sum[:8])
+
+	var current string
+	err := db.Raw(`SELECT coalesce(col_description(attrelid, attnum), '') FROM pg_attribute
+		WHERE attrelid = ?::regclass AND attname = 'search_vector'`, table).Scan(&current).Error
+	if err != nil || current == version {
+		return err
+	}
+
+	if err := db.Exec("UPDATE " + table + " SET search_vector = NULL").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM search_lexicon WHERE table_name = ?", table).Error; err != nil {
+		return err
+	}
+	return db.Exec("COMMENT ON COLUMN " + table + ".search_vector IS '" + version + "'").Error
+}
+
+// Reindex rebuilds the search vectors and the lexicon of every row, or with
+// all set to false only of rows that have none.
+func Reindex(db *gorm.DB, all bool) error {
+	if all {
+		if err := db.Exec("DELETE FROM search_lexicon").Error; err != nil {
+			return err
+		}
+	}
+
+	for _, table := range tables {
+		query := "UPDATE " + table + " SET search_vector = " + documents[table]
+		if !all {
+			query += " WHERE search_vector IS NULL"
+		}
+		if err := db.Exec(query).Error; err != nil {
+			return err
+		}
+
+		lexicon := lexiconSQL(table) + "NOT EXISTS (SELECT 1 FROM search_lexicon l WHERE l.table_name = '" + table + "' AND l.row_id = t.id)"
+		if err := db.Exec(lexicon).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package search
+
+import "github.com/google/uuid"
+
+// Entity types in search results
+const (
+	TypePost       = "post"
+	TypeProject    = "project"
+	TypeExperience = "experience"
+)
+
+// Result is one match, ranked by how well it matches and where: a hit in
+// the title counts more than one in the body. Slug is empty for
+// experiences.
+type Result struct {
+	Type    string
+	ID      uuid.UUID
+	Title   string
+	Slug    string
+	Rank    float64
+	Snippet string
+}
+
+// Suggestion is the closest indexed word to a query term that found
+// nothing.
+type Suggestion struct {
+	Term string
+	Word string
+}
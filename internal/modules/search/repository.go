@@ -0,0 +1,105 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Search(tsquery string, now time.Time, limit, offset int) ([]Result, error)
+	Count(tsquery string, now time.Time) (int64, error)
+	Suggest(terms []string, now time.Time) ([]Suggestion, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Public visibility of each table, matching the rules of the public
+// listings. Drafts and hidden projects never appear in results or
+// suggestions.
+const (
+	postsVisible    = "(is_published OR publish_at <= @now) AND (unpublish_at IS NULL OR unpublish_at > @now)"
+	projectsVisible = "(visible_from IS NULL OR visible_from <= @now) AND (visible_until IS NULL OR visible_until > @now)"
+)
+
+// matchesSQL selects the visible rows of every table that match @query,
+// with the text their snippet is cut from.
+const matchesSQL = `
+	SELECT 'post' AS type, id, title, slug, ts_rank(search_vector, q.query) AS rank,
+		coalesce(summary, '') || ' ' || ` + contentText + ` AS body
+	FROM posts, q
+	WHERE search_vector @@ q.query AND ` + postsVisible + `
+	UNION ALL
+	SELECT 'project', id, title, slug, ts_rank(search_vector, q.query),
+		coalesce(description, '') || ' ' || ` + contentText + `
+	FROM projects, q
+	WHERE search_vector @@ q.query AND ` + projectsVisible + `
+	UNION ALL
+	SELECT 'experience', id, position || ' at ' || company, '', ts_rank(search_vector, q.query),
+		coalesce(description, '')
+	FROM experiences, q
+	WHERE search_vector @@ q.query`
+
+// headlineOptions marks matches with private-use characters, which the
+// service escapes and turns into <mark> tags.
+const headlineOptions = `StartSel=` + markStart + `, StopSel=` + markStop + `, MaxFragments=2, MaxWords=25, MinWords=10, FragmentDelimiter=" … "`
+
+func (r *repository) Search(tsquery string, now time.Time, limit, offset int) ([]Result, error) {
+	var results []Result
+	err := r.db.Raw(`
+		WITH q AS (SELECT to_tsquery('simple', @query) AS query),
+		matches AS (`+matchesSQL+`)
+		SELECT type, id, title, slug, rank, ts_headline('simple', body, q.query, @options) AS snippet
+		FROM matches, q
+		ORDER BY rank DESC, title
+		LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{
+			"query":   tsquery,
+			"now":     now,
+			"options": headlineOptions,
+			"limit":   limit,
+			"offset":  offset,
+		}).Scan(&results).Error
+	return results, err
+}
+
+func (r *repository) Count(tsquery string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		WITH q AS (SELECT to_tsquery('simple', @query) AS query),
+		matches AS (`+matchesSQL+`)
+		SELECT count(*) FROM matches`,
+		map[string]interface{}{
+			"query": tsquery,
+			"now":   now,
+		}).Scan(&count).Error
+	return count, err
+}
+
+// Suggest finds, for each term, the most similar word by trigram similarity
+// among the lexicon words of visible content. Terms without a similar word
+// are left out.
+func (r *repository) Suggest(terms []string, now time.Time) ([]Suggestion, error) {
+	var suggestions []Suggestion
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (term) term, word
+		FROM unnest(CAST(@terms AS text[])) AS term
+		JOIN search_lexicon l ON l.word % term
+		WHERE (l.table_name = 'posts' AND EXISTS (SELECT 1 FROM posts WHERE id = l.row_id AND `+postsVisible+`))
+			OR (l.table_name = 'projects' AND EXISTS (SELECT 1 FROM projects WHERE id = l.row_id AND `+projectsVisible+`))
+			OR (l.table_name = 'experiences' AND EXISTS (SELECT 1 FROM experiences WHERE id = l.row_id))
+		ORDER BY term, similarity(word, term) DESC, word`,
+		map[string]interface{}{
+			// An array literal; terms only contain letters and digits
+			"terms": "{" + strings.Join(terms, ",") + "}",
+			"now":   now,
+		}).Scan(&suggestions).Error
+	return suggestions, err
+}
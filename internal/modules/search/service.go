@@ -0,0 +1,149 @@
+package search
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+)
+
+var ErrQueryRequired = errors.New("query must contain a letter or digit")
+
+// Markers placed around matches by ts_headline. They are private-use
+// characters, which ordinary content does not contain.
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+)
+
+const (
+	maxTerms          = 10
+	minSuggestionTerm = 3 // shorter terms are too ambiguous to correct
+)
+
+var termPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type Service interface {
+	Search(query string, page, limit int) (*Response, error)
+}
+
+// Response is a page of results. Suggestion is set when nothing matched and
+// a similar query would: the query with each unknown word replaced by the
+// closest indexed one.
+type Response struct {
+	Results    pagination.PaginatedResponse
+	Suggestion string
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// terms splits a query into lower-case words, ignoring punctuation and
+// tsquery operators.
+func terms(query string) []string {
+	words := termPattern.FindAllString(strings.ToLower(query), -1)
+	if len(words) > maxTerms {
+		words = words[:maxTerms]
+	}
+	return words
+}
+
+// toTSQuery requires every term and matches each as a prefix, so that
+// partial words typed so far already find results.
+func toTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// snippetHTML escapes a headline and turns the match markers into <mark>
+// tags.
+func snippetHTML(snippet string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, markStart, "<mark>")
+	return strings.ReplaceAll(snippet, markStop, "</mark>")
+}
+
+func (s *service) Search(query string, page, limit int) (*Response, error) {
+	words := terms(query)
+	if len(words) == 0 {
+		return nil, ErrQueryRequired
+	}
+
+	p := pagination.Pagination{
+		Page:  page,
+		Limit: limit,
+	}
+	now := time.Now()
+	tsquery := toTSQuery(words)
+
+	results, err := s.repo.Search(tsquery, now, p.Limit, p.Offset())
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Snippet = snippetHTML(results[i].Snippet)
+	}
+
+	total, err := s.repo.Count(tsquery, now)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Response{Results: pagination.NewResponse(results, total, p)}
+	if total == 0 {
+		if res.Suggestion, err = s.suggest(words, now); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// suggest builds a "did you mean" query, or returns an empty string when no
+// term has a similar indexed word.
+func (s *service) suggest(words []string, now time.Time) (string, error) {
+	var candidates []string
+	for _, word := range words {
+		if len([]rune(word)) >= minSuggestionTerm {
+			candidates = append(candidates, word)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	suggestions, err := s.repo.Suggest(candidates, now)
+	if err != nil {
+		return "", err
+	}
+
+	replacements := make(map[string]string, len(suggestions))
+	for _, suggestion := range suggestions {
+		replacements[suggestion.Term] = suggestion.Word
+	}
+
+	changed := false
+	suggested := make([]string, len(words))
+	for i, word := range words {
+		suggested[i] = word
+		if replacement, ok := replacements[word]; ok && replacement != word {
+			suggested[i] = replacement
+			changed = true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(suggested, " "), nil
+}
@@ -0,0 +1,70 @@
+package seo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Sitemap serves /sitemap.xml: the public pages of the site, or a sitemap
+// index when they do not fit in one file.
+func (h *Handler) Sitemap(c *gin.Context) {
+	body, err := h.service.Sitemap()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to build sitemap", err.Error())
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// SitemapPage serves /sitemaps/:page, e.g. /sitemaps/2.xml, one part of a
+// sitemap split behind the index.
+func (h *Handler) SitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || !strings.HasSuffix(c.Param("page"), ".xml") {
+		response.Error(c, http.StatusNotFound, "Sitemap not found", ErrPageNotFound.Error())
+		return
+	}
+
+	body, err := h.service.SitemapPage(page)
+	if err != nil {
+		if errors.Is(err, ErrPageNotFound) {
+			response.Error(c, http.StatusNotFound, "Sitemap not found", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to build sitemap", err.Error())
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// Robots serves /robots.txt.
+func (h *Handler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, h.service.Robots())
+}
+
+// IndexNowKey serves /indexnow.txt, the key file search engines fetch to
+// verify IndexNow notifications. Not found when IndexNow is not set up.
+func (h *Handler) IndexNowKey(c *gin.Context) {
+	key := h.service.IndexNowKey()
+	if key == "" {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	c.String(http.StatusOK, key)
+}
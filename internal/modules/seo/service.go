@@ -0,0 +1,141 @@
+package seo
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
+	"github.com/prakoso-id/personal-backend/internal/modules/projects"
+)
+
+// maxSitemapURLs is the most URLs the sitemap protocol allows in one file.
+const maxSitemapURLs = 50000
+
+var ErrPageNotFound = errors.New("sitemap page not found")
+
+// Entry is one public page listed in the sitemap. LastMod is zero when it
+// is not known.
+type Entry struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type Service interface {
+	Sitemap() ([]byte, error)
+	SitemapPage(page int) ([]byte, error)
+	Robots() string
+	IndexNowKey() string
+}
+
+type service struct {
+	posts    posts.Service
+	projects projects.Service
+	profiles profiles.Service
+	site     config.SiteConfig
+	seo      config.SEOConfig
+}
+
+func NewService(postService posts.Service, projectService projects.Service, profileService profiles.Service, cfg *config.Config) Service {
+	return &service{
+		posts:    postService,
+		projects: projectService,
+		profiles: profileService,
+		site:     cfg.Site,
+		seo:      cfg.SEO,
+	}
+}
+
+// Sitemap lists every public page, or, when there are more than fit in one
+// sitemap, is an index of the pages served by SitemapPage.
+func (s *service) Sitemap() ([]byte, error) {
+	entries, err := s.entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) <= s.pageSize() {
+		return renderURLSet(entries)
+	}
+	return renderIndex(entries, s.pageSize(), s.site.URL)
+}
+
+// SitemapPage returns one part of a sitemap split behind an index.
+func (s *service) SitemapPage(page int) ([]byte, error) {
+	entries, err := s.entries()
+	if err != nil {
+		return nil, err
+	}
+	part := pageOf(entries, page, s.pageSize())
+	if len(part) == 0 {
+		return nil, ErrPageNotFound
+	}
+	return renderURLSet(part)
+}
+
+// entries lists the public pages: the home page, published posts and
+// visible projects.
+func (s *service) entries() ([]Entry, error) {
+	profile, err := s.profiles.GetProfile()
+	if err != nil {
+		return nil, err
+	}
+	// The home page shows the profile
+	home := Entry{Loc: strings.TrimRight(s.site.URL, "/") + "/"}
+	if profile != nil {
+		home.LastMod = profile.UpdatedAt
+	}
+	entries := []Entry{home}
+
+	published, err := s.posts.GetPublished("", 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range published {
+		entries = append(entries, Entry{Loc: s.site.PostURL(post.Slug), LastMod: post.UpdatedAt})
+	}
+
+	visible, err := s.projects.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range visible {
+		entries = append(entries, Entry{Loc: s.site.ProjectURL(project.ID.String(), project.Slug), LastMod: project.UpdatedAt})
+	}
+	return entries, nil
+}
+
+func (s *service) pageSize() int {
+	if s.seo.SitemapMaxURLs <= 0 || s.seo.SitemapMaxURLs > maxSitemapURLs {
+		return maxSitemapURLs
+	}
+	return s.seo.SitemapMaxURLs
+}
+
+// Robots builds robots.txt. The sitemap is referenced on the site's own
+// host, where crawlers accept it for the site's pages.
+func (s *service) Robots() string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if !s.seo.AllowIndexing {
+		b.WriteString("Disallow: /\n")
+	} else {
+		disallowed := 0
+		for _, path := range strings.Split(s.seo.RobotsDisallow, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				b.WriteString("Disallow: " + path + "\n")
+				disallowed++
+			}
+		}
+		if disallowed == 0 {
+			b.WriteString("Allow: /\n")
+		}
+	}
+	b.WriteString("\nSitemap: " + strings.TrimRight(s.site.URL, "/") + "/sitemap.xml\n")
+	return b.String()
+}
+
+func (s *service) IndexNowKey() string {
+	return s.seo.IndexNowKey
+}
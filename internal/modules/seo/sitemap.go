@@ -0,0 +1,83 @@
+package seo
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+func lastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// renderURLSet writes entries as a sitemap.
+func renderURLSet(entries []Entry) ([]byte, error) {
+	set := urlSet{XMLNS: sitemapNS, URLs: make([]sitemapURL, 0, len(entries))}
+	for _, entry := range entries {
+		set.URLs = append(set.URLs, sitemapURL{Loc: entry.Loc, LastMod: lastMod(entry.LastMod)})
+	}
+	return marshal(set)
+}
+
+// renderIndex writes a sitemap index of the pages entries are split into,
+// found at siteURL/sitemaps/<n>.xml, each dated by its latest change.
+func renderIndex(entries []Entry, pageSize int, siteURL string) ([]byte, error) {
+	index := sitemapIndex{XMLNS: sitemapNS}
+	for page := 1; (page-1)*pageSize < len(entries); page++ {
+		var latest time.Time
+		for _, entry := range pageOf(entries, page, pageSize) {
+			if entry.LastMod.After(latest) {
+				latest = entry.LastMod
+			}
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     strings.TrimRight(siteURL, "/") + "/sitemaps/" + strconv.Itoa(page) + ".xml",
+			LastMod: lastMod(latest),
+		})
+	}
+	return marshal(index)
+}
+
+// pageOf returns the entries of a 1-based page, or nil past the end.
+func pageOf(entries []Entry, page, pageSize int) []Entry {
+	start := (page - 1) * pageSize
+	if page < 1 || start >= len(entries) {
+		return nil
+	}
+	end := start + pageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[start:end]
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,317 @@
+package series
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func toSeriesResponse(series *Series) gin.H {
+	return gin.H{
+		"id":          series.ID,
+		"title":       series.Title,
+		"slug":        series.Slug,
+		"slug_pinned": series.SlugPinned,
+		"description": series.Description,
+		"cover_url":   series.CoverURL,
+		"created_at":  series.CreatedAt,
+		"updated_at":  series.UpdatedAt,
+	}
+}
+
+// toPartResponse describes a post as part n of a series. Admin responses
+// include the publication state, since drafts are listed there too.
+func toPartResponse(post *posts.Post, n int, admin bool) gin.H {
+	part := gin.H{
+		"part":         n,
+		"id":           post.ID,
+		"title":        post.Title,
+		"slug":         post.Slug,
+		"summary":      post.Summary,
+		"reading_time": post.ReadingTime,
+		"published_at": post.PublishedAt,
+	}
+	if admin {
+		part["is_published"] = post.IsPublished
+		part["publish_at"] = post.PublishAt
+		part["unpublish_at"] = post.UnpublishAt
+	}
+	return part
+}
+
+func toDetailResponse(series *Series, members []posts.Post, admin bool) gin.H {
+	parts := make([]gin.H, 0, len(members))
+	for i := range members {
+		parts = append(parts, toPartResponse(&members[i], i+1, admin))
+	}
+	data := toSeriesResponse(series)
+	data["posts"] = parts
+	return data
+}
+
+// GetPublicSeries godoc
+// @Summary      Public - Get Series by Slug
+// @Description  A series and its published posts in reading order, numbered from 1. A series without published posts is not found. A slug the series had before its title changed answers 301 with the current slug in data.slug and the canonical URL in the Location header.
+// @Tags         Public - Series
+// @Produce      json
+// @Param        slug  path      string  true  "Series slug"
+// @Success      200   {object}  map[string]interface{}
+// @Success      301   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /public/series/{slug} [get]
+func (h *Handler) GetPublicSeries(c *gin.Context) {
+	slug := c.Param("slug")
+	series, members, err := h.service.GetPublic(slug)
+	if err != nil {
+		if !errors.Is(err, ErrSeriesNotFound) {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch series", err.Error())
+			return
+		}
+		current, err := h.service.ResolveSlug(slug)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch series", err.Error())
+			return
+		}
+		if current != "" {
+			c.Header("Location", "/api/public/series/"+url.PathEscape(current))
+			response.Success(c, http.StatusMovedPermanently, "Series moved", gin.H{"slug": current})
+			return
+		}
+		response.Error(c, http.StatusNotFound, "Series not found", ErrSeriesNotFound.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Series fetched successfully", toDetailResponse(series, members, false))
+}
+
+// GetAdminSeries godoc
+// @Summary      Admin - Get All Series
+// @Description  Retrieve a paginated list of series with the number of posts in each, drafts included
+// @Tags         Admin - Series
+// @Produce      json
+// @Param        page   query    int  false  "Page number" default(1)
+// @Param        limit  query    int  false  "Items per page" default(10)
+// @Security     BearerAuth
+// @Success      200  {object}  pagination.PaginatedResponse
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series [get]
+func (h *Handler) GetAdminSeries(c *gin.Context) {
+	p := pagination.FromContext(c)
+	res, err := h.service.GetAllAdmin(p.Page, p.Limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch series", err.Error())
+		return
+	}
+
+	list := res.Data.([]Series)
+	data := make([]gin.H, 0, len(list))
+	for i := range list {
+		item := toSeriesResponse(&list[i])
+		item["post_count"] = list[i].PostCount
+		data = append(data, item)
+	}
+	res.Data = data
+	response.Success(c, http.StatusOK, "Series fetched successfully", res)
+}
+
+// GetAdminSeriesByID godoc
+// @Summary      Admin - Get Series
+// @Description  A series and all its posts in reading order, drafts included
+// @Tags         Admin - Series
+// @Produce      json
+// @Param        id  path  string  true  "Series ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series/{id} [get]
+func (h *Handler) GetAdminSeriesByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	series, members, err := h.service.GetAdmin(id)
+	if err != nil {
+		if errors.Is(err, ErrSeriesNotFound) {
+			response.Error(c, http.StatusNotFound, "Series not found", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch series", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Series fetched successfully", toDetailResponse(series, members, true))
+}
+
+// CreateSeries godoc
+// @Summary      Admin - Create Series
+// @Description  Create a series. Without a slug, a free one is generated from the title; a custom slug is pinned and answers 409 when already taken. Add posts with PUT /admin/series/{id}/posts.
+// @Tags         Admin - Series
+// @Accept       json
+// @Produce      json
+// @Param        request  body  CreateSeriesRequest  true  "Series Data"
+// @Security     BearerAuth
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series [post]
+func (h *Handler) CreateSeries(c *gin.Context) {
+	var req CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	series, err := h.service.Create(audit.ActorFromContext(c), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, slugs.ErrInvalidSlug), errors.Is(err, slugs.ErrReservedSlug):
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+		case errors.Is(err, slugs.ErrSlugTaken):
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to create series", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusCreated, "Series created successfully", toSeriesResponse(series))
+}
+
+// UpdateSeries godoc
+// @Summary      Admin - Update Series
+// @Description  Update the title, slug, description and cover of a series. Its old slug keeps redirecting to the new one.
+// @Tags         Admin - Series
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string               true  "Series ID"
+// @Param        request  body  UpdateSeriesRequest  true  "Series Data"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series/{id} [put]
+func (h *Handler) UpdateSeries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	series, err := h.service.Update(audit.ActorFromContext(c), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSeriesNotFound):
+			response.Error(c, http.StatusNotFound, "Series not found", err.Error())
+		case errors.Is(err, slugs.ErrInvalidSlug), errors.Is(err, slugs.ErrReservedSlug):
+			response.Error(c, http.StatusBadRequest, "Invalid slug", err.Error())
+		case errors.Is(err, slugs.ErrSlugTaken):
+			response.Error(c, http.StatusConflict, "Slug already taken", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update series", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusOK, "Series updated successfully", toSeriesResponse(series))
+}
+
+// DeleteSeries godoc
+// @Summary      Admin - Delete Series
+// @Description  Delete a series. Its posts are kept and no longer belong to a series.
+// @Tags         Admin - Series
+// @Produce      json
+// @Param        id  path  string  true  "Series ID"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series/{id} [delete]
+func (h *Handler) DeleteSeries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	if err := h.service.Delete(audit.ActorFromContext(c), id); err != nil {
+		if errors.Is(err, ErrSeriesNotFound) {
+			response.Error(c, http.StatusNotFound, "Series not found", err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to delete series", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Series deleted successfully", nil)
+}
+
+// SetSeriesPosts godoc
+// @Summary      Admin - Set Series Posts
+// @Description  Replace the posts of a series with post_ids, in reading order. Send the current posts in a new order to reorder them, or an empty list to empty the series. A post belongs to one series at most; listing a post of another series answers 409.
+// @Tags         Admin - Series
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string           true  "Series ID"
+// @Param        request  body  SetPostsRequest  true  "Posts in order"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/series/{id}/posts [put]
+func (h *Handler) SetSeriesPosts(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	var req SetPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	series, members, err := h.service.SetPosts(audit.ActorFromContext(c), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSeriesNotFound):
+			response.Error(c, http.StatusNotFound, "Series not found", err.Error())
+		case errors.Is(err, ErrInvalidPosts):
+			response.Error(c, http.StatusBadRequest, "Invalid posts", err.Error())
+		case errors.Is(err, ErrPostInOtherSeries):
+			response.Error(c, http.StatusConflict, "Post already in a series", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update series posts", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusOK, "Series posts updated successfully", toDetailResponse(series, members, true))
+}
@@ -0,0 +1,68 @@
+package series
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"gorm.io/gorm"
+)
+
+// Package-level base URL for constructing full cover URLs at response time.
+var baseURL string
+
+// SetBaseURL sets the base URL used to construct full cover URLs.
+// Should be called once during application initialization.
+func SetBaseURL(url string) {
+	baseURL = strings.TrimRight(url, "/")
+}
+
+// Series is an ordered collection of posts, such as a multi-part tutorial.
+type Series struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Title       string    `gorm:"type:varchar(255);not null"`
+	Slug        string    `gorm:"type:varchar(255);unique;not null"`
+	SlugPinned  bool      `gorm:"default:false"` // custom slug, kept when the title changes
+	Description string    `gorm:"type:text"`
+	CoverURL    string    `gorm:"type:varchar(512)"`
+	PostCount   int64     `gorm:"->;-:migration"` // filled by the admin listing only
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Membership puts a post in a series. A post belongs to one series at most;
+// the posts of a series are ordered by Position.
+type Membership struct {
+	PostID   uuid.UUID   `gorm:"type:uuid;primary_key"`
+	SeriesID uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Position int         `gorm:"not null"`
+	Series   *Series     `gorm:"constraint:OnDelete:CASCADE"`
+	Post     *posts.Post `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+func (Membership) TableName() string {
+	return "series_posts"
+}
+
+// AfterFind is a GORM hook that prepends the base URL to CoverURL after
+// loading from the database, so the API response contains the full URL.
+func (s *Series) AfterFind(tx *gorm.DB) error {
+	if baseURL != "" && s.CoverURL != "" && !strings.HasPrefix(s.CoverURL, "http") {
+		s.CoverURL = baseURL + s.CoverURL
+	}
+	return nil
+}
+
+// BeforeSave is a GORM hook that strips the base URL from CoverURL before
+// saving to the database, ensuring only relative paths are stored.
+func (s *Series) BeforeSave(tx *gorm.DB) error {
+	if baseURL != "" && strings.HasPrefix(s.CoverURL, baseURL) {
+		s.CoverURL = strings.TrimPrefix(s.CoverURL, baseURL)
+	}
+	return nil
+}
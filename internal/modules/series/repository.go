@@ -0,0 +1,152 @@
+package series
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(series *Series) error
+	Update(series *Series) error
+	Delete(id uuid.UUID) error
+	FindByID(id uuid.UUID) (*Series, error)
+	FindBySlug(slug string) (*Series, error)
+	FindAll(limit, offset int) ([]Series, error)
+	Count() (int64, error)
+	FindPosts(seriesID uuid.UUID, publishedOnly bool) ([]posts.Post, error)
+	CountPosts(postIDs []uuid.UUID) (int64, error)
+	CountInOtherSeries(postIDs []uuid.UUID, seriesID uuid.UUID) (int64, error)
+	SetPosts(seriesID uuid.UUID, postIDs []uuid.UUID) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(series *Series) error {
+	return slugConflict(r.db.Create(series).Error)
+}
+
+func (r *repository) Update(series *Series) error {
+	return slugConflict(r.db.Save(series).Error)
+}
+
+// slugConflict reports a violated unique slug, the only unique column
+// written here, as taken: another series got the slug after it was checked.
+func slugConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return slugs.ErrSlugTaken
+	}
+	return err
+}
+
+// Delete deletes a series. Its posts stay; only their membership goes.
+func (r *repository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&Membership{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Series{}, "id = ?", id).Error
+	})
+}
+
+func (r *repository) FindByID(id uuid.UUID) (*Series, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *repository) FindBySlug(slug string) (*Series, error) {
+	return r.findOne("slug = ?", slug)
+}
+
+func (r *repository) findOne(query string, args ...interface{}) (*Series, error) {
+	var series Series
+	err := r.db.Where(query, args...).First(&series).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindAll returns series with the number of posts in each, most recently
+// created first.
+func (r *repository) FindAll(limit, offset int) ([]Series, error) {
+	var list []Series
+	query := r.db.
+		Select("series.*, (SELECT COUNT(*) FROM series_posts WHERE series_posts.series_id = series.id) AS post_count").
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+func (r *repository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&Series{}).Count(&count).Error
+	return count, err
+}
+
+// FindPosts returns the posts of a series in order. With publishedOnly,
+// only the posts that are public now.
+func (r *repository) FindPosts(seriesID uuid.UUID, publishedOnly bool) ([]posts.Post, error) {
+	var list []posts.Post
+	query := r.db.Preload("Tags").Preload("Images").
+		Joins("JOIN series_posts ON series_posts.post_id = posts.id AND series_posts.series_id = ?", seriesID).
+		Order("series_posts.position")
+	if publishedOnly {
+		now := time.Now()
+		query = query.Where("(posts.is_published = ? OR posts.publish_at <= ?) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)", true, now, now)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+// CountPosts returns how many of the posts exist.
+func (r *repository) CountPosts(postIDs []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&posts.Post{}).Where("id IN ?", postIDs).Count(&count).Error
+	return count, err
+}
+
+// CountInOtherSeries returns how many of the posts belong to a series other
+// than seriesID.
+func (r *repository) CountInOtherSeries(postIDs []uuid.UUID, seriesID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&Membership{}).Where("post_id IN ? AND series_id <> ?", postIDs, seriesID).Count(&count).Error
+	return count, err
+}
+
+// SetPosts replaces the posts of a series with postIDs, in that order.
+func (r *repository) SetPosts(seriesID uuid.UUID, postIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesID).Delete(&Membership{}).Error; err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+		memberships := make([]Membership, len(postIDs))
+		for i, id := range postIDs {
+			memberships[i] = Membership{PostID: id, SeriesID: seriesID, Position: i + 1}
+		}
+		err := tx.Create(&memberships).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			// another series took one of the posts after it was checked
+			return ErrPostInOtherSeries
+		}
+		return err
+	})
+}
@@ -0,0 +1,283 @@
+package series
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+)
+
+var (
+	ErrSeriesNotFound    = errors.New("series not found")
+	ErrInvalidPosts      = errors.New("post_ids must list existing posts, each once")
+	ErrPostInOtherSeries = errors.New("a post can only be part of one series; remove it from the other series first")
+)
+
+type Service interface {
+	Create(actor audit.Actor, req *CreateSeriesRequest) (*Series, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdateSeriesRequest) (*Series, error)
+	Delete(actor audit.Actor, id uuid.UUID) error
+	GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error)
+	GetAdmin(id uuid.UUID) (*Series, []posts.Post, error)
+	GetPublic(slug string) (*Series, []posts.Post, error)
+	ResolveSlug(oldSlug string) (string, error)
+	SetPosts(actor audit.Actor, id uuid.UUID, req *SetPostsRequest) (*Series, []posts.Post, error)
+}
+
+type service struct {
+	repo  Repository
+	audit audit.Service
+	slugs slugs.Service
+}
+
+func NewService(repo Repository, auditService audit.Service, slugService slugs.Service) Service {
+	return &service{repo: repo, audit: auditService, slugs: slugService}
+}
+
+type CreateSeriesRequest struct {
+	Title       string  `json:"title" binding:"required"`
+	Slug        *string `json:"slug"` // custom slug; empty to generate one from the title
+	Description string  `json:"description"`
+	CoverURL    string  `json:"cover_url"` // file_path of an uploaded image, or any URL
+}
+
+type UpdateSeriesRequest struct {
+	Title       string  `json:"title" binding:"required"`
+	Slug        *string `json:"slug"` // custom slug; empty to generate one from the title
+	Description string  `json:"description"`
+	CoverURL    string  `json:"cover_url"`
+}
+
+type SetPostsRequest struct {
+	PostIDs []string `json:"post_ids"` // every post of the series, in reading order
+}
+
+// membershipState is what the audit log records of the posts of a series.
+type membershipState struct {
+	PostIDs []uuid.UUID
+}
+
+// assignSlug sets the slug of a series the way posts get theirs: a custom
+// slug is checked and pinned, otherwise a free one is generated from the
+// title.
+func (s *service) assignSlug(series *Series, custom *string) error {
+	if custom != nil {
+		if *custom != "" {
+			if err := s.slugs.Check(slugs.EntitySeries, series.ID, *custom); err != nil {
+				return err
+			}
+			series.Slug = *custom
+			series.SlugPinned = true
+			return nil
+		}
+		series.SlugPinned = false
+	}
+	if series.SlugPinned {
+		return nil
+	}
+
+	generated, err := s.slugs.Generate(slugs.EntitySeries, series.ID, series.Title, series.Slug)
+	if err != nil {
+		return err
+	}
+	series.Slug = generated
+	return nil
+}
+
+func (s *service) Create(actor audit.Actor, req *CreateSeriesRequest) (*Series, error) {
+	series := &Series{
+		Title:       req.Title,
+		Description: req.Description,
+		CoverURL:    req.CoverURL,
+	}
+	if err := s.assignSlug(series, req.Slug); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(series); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionCreate, "series", series.ID.String(), nil, series)
+	s.slugs.Record(slugs.EntitySeries, series.ID, "", series.Slug)
+	return s.repo.FindByID(series.ID)
+}
+
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdateSeriesRequest) (*Series, error) {
+	series, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return nil, ErrSeriesNotFound
+	}
+	before := *series
+
+	series.Title = req.Title
+	series.Description = req.Description
+	series.CoverURL = req.CoverURL
+	if err := s.assignSlug(series, req.Slug); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(series); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "series", series.ID.String(), before, series)
+	s.slugs.Record(slugs.EntitySeries, series.ID, before.Slug, series.Slug)
+	return s.repo.FindByID(series.ID)
+}
+
+// Delete deletes a series. Its posts are kept.
+func (s *service) Delete(actor audit.Actor, id uuid.UUID) error {
+	series, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if series == nil {
+		return ErrSeriesNotFound
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.audit.Record(actor, audit.ActionDelete, "series", series.ID.String(), series, nil)
+	s.slugs.Forget(slugs.EntitySeries, id)
+	return nil
+}
+
+func (s *service) GetAllAdmin(page, limit int) (*pagination.PaginatedResponse, error) {
+	p := pagination.Pagination{
+		Page:  page,
+		Limit: limit,
+	}
+
+	list, err := s.repo.FindAll(p.Limit, p.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	res := pagination.NewResponse(list, total, p)
+	return &res, nil
+}
+
+// GetAdmin returns a series and all its posts, drafts included, in order.
+func (s *service) GetAdmin(id uuid.UUID) (*Series, []posts.Post, error) {
+	series, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if series == nil {
+		return nil, nil, ErrSeriesNotFound
+	}
+
+	members, err := s.repo.FindPosts(id, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, members, nil
+}
+
+// GetPublic returns a series and its visible posts in order. A series
+// without any is not found, so drafts do not reveal it.
+func (s *service) GetPublic(slug string) (*Series, []posts.Post, error) {
+	series, err := s.repo.FindBySlug(slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	if series == nil {
+		return nil, nil, ErrSeriesNotFound
+	}
+
+	members, err := s.repo.FindPosts(series.ID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil, ErrSeriesNotFound
+	}
+	return series, members, nil
+}
+
+// ResolveSlug returns the current slug of the public series that used to be
+// at oldSlug, or an empty string when there is none.
+func (s *service) ResolveSlug(oldSlug string) (string, error) {
+	id, err := s.slugs.Resolve(slugs.EntitySeries, oldSlug)
+	if err != nil || id == nil {
+		return "", err
+	}
+	series, err := s.repo.FindByID(*id)
+	if err != nil || series == nil {
+		return "", err
+	}
+	members, err := s.repo.FindPosts(series.ID, true)
+	if err != nil || len(members) == 0 {
+		return "", err
+	}
+	return series.Slug, nil
+}
+
+// SetPosts replaces the posts of a series with the listed ones, in that
+// order. Sending the current posts in a new order reorders them; an empty
+// list empties the series.
+func (s *service) SetPosts(actor audit.Actor, id uuid.UUID, req *SetPostsRequest) (*Series, []posts.Post, error) {
+	series, before, err := s.GetAdmin(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.PostIDs))
+	postIDs := make([]uuid.UUID, 0, len(req.PostIDs))
+	for _, idStr := range req.PostIDs {
+		postID, err := uuid.Parse(idStr)
+		if err != nil || seen[postID] {
+			return nil, nil, ErrInvalidPosts
+		}
+		seen[postID] = true
+		postIDs = append(postIDs, postID)
+	}
+
+	if len(postIDs) > 0 {
+		found, err := s.repo.CountPosts(postIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		if found != int64(len(postIDs)) {
+			return nil, nil, ErrInvalidPosts
+		}
+		taken, err := s.repo.CountInOtherSeries(postIDs, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if taken > 0 {
+			return nil, nil, ErrPostInOtherSeries
+		}
+	}
+
+	if err := s.repo.SetPosts(id, postIDs); err != nil {
+		return nil, nil, err
+	}
+
+	after, err := s.repo.FindPosts(id, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.audit.Record(actor, audit.ActionUpdate, "series", series.ID.String(), membershipOf(before), membershipOf(after))
+	return series, after, nil
+}
+
+func membershipOf(list []posts.Post) membershipState {
+	state := membershipState{PostIDs: make([]uuid.UUID, len(list))}
+	for i := range list {
+		state.PostIDs[i] = list[i].ID
+	}
+	return state
+}
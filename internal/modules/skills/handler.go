@@ -1,20 +1,22 @@
 package skills
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
 	"github.com/prakoso-id/personal-backend/internal/utils/response"
-	"gorm.io/gorm"
 )
 
 type Handler struct {
-	db *gorm.DB
+	service Service
 }
 
-func NewHandler(db *gorm.DB) *Handler {
-	return &Handler{db: db}
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
 }
 
 // GetAll godoc
@@ -27,29 +29,14 @@ func NewHandler(db *gorm.DB) *Handler {
 // @Router       /public/skills [get]
 func (h *Handler) GetAll(c *gin.Context) {
 	p := pagination.FromContext(c)
-	var skills []Skill
-	var total int64
-
-	if err := h.db.Model(&Skill{}).Count(&total).Error; err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to count skills", err.Error())
-		return
-	}
-
-	if err := h.db.Scopes(pagination.Paginate(p)).Find(&skills).Error; err != nil {
+	res, err := h.service.GetAll(p.Page, p.Limit)
+	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to fetch skills", err.Error())
 		return
 	}
-
-	res := pagination.NewResponse(skills, total, p)
 	response.Success(c, http.StatusOK, "Skills fetched successfully", res)
 }
 
-type CreateSkillRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Category string `json:"category" binding:"required"`
-	IconURL  string `json:"icon_url"`
-}
-
 // Create godoc
 // @Summary      Admin - Create Skill
 // @Description  Create a new skill
@@ -69,13 +56,8 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
-	skill := Skill{
-		Name:     req.Name,
-		Category: req.Category,
-		IconURL:  req.IconURL,
-	}
-
-	if err := h.db.Create(&skill).Error; err != nil {
+	skill, err := h.service.Create(audit.ActorFromContext(c), &req)
+	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create skill", err.Error())
 		return
 	}
@@ -83,12 +65,6 @@ func (h *Handler) Create(c *gin.Context) {
 	response.Success(c, http.StatusCreated, "Skill created successfully", skill)
 }
 
-type UpdateSkillRequest struct {
-	Name     string `json:"name"`
-	Category string `json:"category"`
-	IconURL  string `json:"icon_url"`
-}
-
 // Update godoc
 // @Summary      Admin - Update Skill
 // @Description  Update an existing skill
@@ -104,30 +80,24 @@ type UpdateSkillRequest struct {
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/skills/{id} [put]
 func (h *Handler) Update(c *gin.Context) {
-	id := c.Param("id")
-	var req UpdateSkillRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
 		return
 	}
 
-	var skill Skill
-	if err := h.db.First(&skill, "id = ?", id).Error; err != nil {
-		response.Error(c, http.StatusNotFound, "Skill not found", "skill not found")
+	var req UpdateSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
 		return
 	}
 
-	if req.Name != "" {
-		skill.Name = req.Name
-	}
-	if req.Category != "" {
-		skill.Category = req.Category
-	}
-	if req.IconURL != "" {
-		skill.IconURL = req.IconURL
-	}
-
-	if err := h.db.Save(&skill).Error; err != nil {
+	skill, err := h.service.Update(audit.ActorFromContext(c), id, &req)
+	if err != nil {
+		if errors.Is(err, ErrSkillNotFound) {
+			response.Error(c, http.StatusNotFound, "Skill not found", err.Error())
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "Failed to update skill", err.Error())
 		return
 	}
@@ -147,8 +117,13 @@ func (h *Handler) Update(c *gin.Context) {
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/skills/{id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.db.Delete(&Skill{}, "id = ?", id).Error; err != nil {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	if err := h.service.Delete(audit.ActorFromContext(c), id); err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to delete skill", err.Error())
 		return
 	}
@@ -0,0 +1,65 @@
+package skills
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(skill *Skill) error
+	Update(skill *Skill) error
+	Delete(id uuid.UUID) error
+	FindByID(id uuid.UUID) (*Skill, error)
+	FindAll(limit, offset int) ([]Skill, error)
+	Count() (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(skill *Skill) error {
+	return r.db.Create(skill).Error
+}
+
+func (r *repository) Update(skill *Skill) error {
+	return r.db.Save(skill).Error
+}
+
+func (r *repository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&Skill{}, "id = ?", id).Error
+}
+
+func (r *repository) FindByID(id uuid.UUID) (*Skill, error) {
+	var skill Skill
+	err := r.db.First(&skill, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &skill, nil
+}
+
+func (r *repository) FindAll(limit, offset int) ([]Skill, error) {
+	var skills []Skill
+	query := r.db
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	err := query.Find(&skills).Error
+	return skills, err
+}
+
+func (r *repository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&Skill{}).Count(&count).Error
+	return count, err
+}
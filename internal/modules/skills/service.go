@@ -0,0 +1,117 @@
+package skills
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/utils/pagination"
+)
+
+type Service interface {
+	Create(actor audit.Actor, req *CreateSkillRequest) (*Skill, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdateSkillRequest) (*Skill, error)
+	Delete(actor audit.Actor, id uuid.UUID) error
+	GetAll(page, limit int) (*pagination.PaginatedResponse, error)
+}
+
+var ErrSkillNotFound = errors.New("skill not found")
+
+type service struct {
+	repo  Repository
+	audit audit.Service
+}
+
+func NewService(repo Repository, auditService audit.Service) Service {
+	return &service{repo: repo, audit: auditService}
+}
+
+type CreateSkillRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Category string `json:"category" binding:"required"`
+	IconURL  string `json:"icon_url"`
+}
+
+// UpdateSkillRequest changes the fields that are not empty.
+type UpdateSkillRequest struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	IconURL  string `json:"icon_url"`
+}
+
+func (s *service) Create(actor audit.Actor, req *CreateSkillRequest) (*Skill, error) {
+	skill := &Skill{
+		Name:     req.Name,
+		Category: req.Category,
+		IconURL:  req.IconURL,
+	}
+	if err := s.repo.Create(skill); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionCreate, "skill", skill.ID.String(), nil, skill)
+	return skill, nil
+}
+
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdateSkillRequest) (*Skill, error) {
+	skill, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if skill == nil {
+		return nil, ErrSkillNotFound
+	}
+	before := *skill
+
+	if req.Name != "" {
+		skill.Name = req.Name
+	}
+	if req.Category != "" {
+		skill.Category = req.Category
+	}
+	if req.IconURL != "" {
+		skill.IconURL = req.IconURL
+	}
+	if err := s.repo.Update(skill); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "skill", skill.ID.String(), before, skill)
+	return skill, nil
+}
+
+func (s *service) Delete(actor audit.Actor, id uuid.UUID) error {
+	skill, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	if skill != nil {
+		s.audit.Record(actor, audit.ActionDelete, "skill", skill.ID.String(), skill, nil)
+	}
+	return nil
+}
+
+func (s *service) GetAll(page, limit int) (*pagination.PaginatedResponse, error) {
+	p := pagination.Pagination{
+		Page:  page,
+		Limit: limit,
+	}
+
+	skills, err := s.repo.FindAll(p.Limit, p.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	res := pagination.NewResponse(skills, total, p)
+	return &res, nil
+}
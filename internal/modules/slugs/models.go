@@ -0,0 +1,39 @@
+package slugs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types that keep a slug history
+const (
+	EntityPost    = "post"
+	EntityProject = "project"
+	EntitySeries  = "series"
+	EntityTag     = "tag"
+)
+
+// entityTables maps each entity type to the table holding its rows.
+var entityTables = map[string]string{
+	EntityPost:    "posts",
+	EntityProject: "projects",
+	EntitySeries:  "series",
+	EntityTag:     "tags",
+}
+
+// History is a slug an entity used before, kept so that links to it can be
+// redirected to the current one. A slug points at no more than one entity
+// of a type, and never at one while another entity uses it as its current
+// slug.
+type History struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	EntityType string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_slug_history_slug"`
+	Slug       string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_history_slug"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt  time.Time // when the entity moved away from the slug
+}
+
+func (History) TableName() string {
+	return "slug_history"
+}
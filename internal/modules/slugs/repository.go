@@ -0,0 +1,76 @@
+package slugs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Save(history *History) error
+	FindBySlug(entityType, slug string) (*History, error)
+	DeleteSlug(entityType, slug string) error
+	DeleteByEntity(entityType string, entityID uuid.UUID) error
+	InUse(entityType, slug string, exceptID uuid.UUID) (current, retired bool, err error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Save records the slug for its entity, taking it over from any entity that
+// used it before.
+func (r *repository) Save(history *History) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(history).Error
+}
+
+func (r *repository) FindBySlug(entityType, slug string) (*History, error) {
+	var history History
+	err := r.db.First(&history, "entity_type = ? AND slug = ?", entityType, slug).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (r *repository) DeleteSlug(entityType, slug string) error {
+	return r.db.Where("entity_type = ? AND slug = ?", entityType, slug).Delete(&History{}).Error
+}
+
+func (r *repository) DeleteByEntity(entityType string, entityID uuid.UUID) error {
+	return r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(&History{}).Error
+}
+
+// InUse reports whether an entity other than exceptID has the slug as its
+// current slug, or had it before.
+func (r *repository) InUse(entityType, slug string, exceptID uuid.UUID) (bool, bool, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return false, false, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	var current int64
+	err := r.db.Table(table).Where("slug = ? AND id <> ?", slug, exceptID).Count(&current).Error
+	if err != nil {
+		return false, false, err
+	}
+
+	var retired int64
+	err = r.db.Model(&History{}).
+		Where("entity_type = ? AND slug = ? AND entity_id <> ?", entityType, slug, exceptID).
+		Count(&retired).Error
+	return current > 0, retired > 0, err
+}
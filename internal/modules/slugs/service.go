@@ -0,0 +1,118 @@
+package slugs
+
+import (
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+type Service interface {
+	Validate(requested string) error
+	Check(entityType string, entityID uuid.UUID, requested string) error
+	Generate(entityType string, entityID uuid.UUID, title, current string) (string, error)
+	Record(entityType string, entityID uuid.UUID, oldSlug, newSlug string)
+	Resolve(entityType, slug string) (*uuid.UUID, error)
+	Forget(entityType string, entityID uuid.UUID)
+}
+
+type service struct {
+	repo     Repository
+	reserved map[string]bool
+}
+
+func NewService(repo Repository, cfg *config.Config) Service {
+	words := append([]string{}, reserved...)
+	words = append(words, strings.Split(cfg.Slug.ReservedWords, ",")...)
+
+	s := &service{repo: repo, reserved: make(map[string]bool, len(words))}
+	for _, word := range words {
+		if word = strings.TrimSpace(strings.ToLower(word)); word != "" {
+			s.reserved[word] = true
+		}
+	}
+	return s
+}
+
+// Check validates a slug a client asked for and makes sure no other entity
+// uses it. A slug another entity used before may be taken over; it then
+// stops redirecting there.
+func (s *service) Check(entityType string, entityID uuid.UUID, requested string) error {
+	if err := s.Validate(requested); err != nil {
+		return err
+	}
+	current, _, err := s.repo.InUse(entityType, requested, entityID)
+	if err != nil {
+		return err
+	}
+	if current {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+// Generate derives a free slug from a title. When the slug is taken, by
+// another entity's current or former slug, or reserved, a numeric suffix is
+// added: the first free of base, base-2, base-3 and so on. An entity whose
+// current slug is already one of these keeps it, so saving without
+// changing the title never moves it.
+func (s *service) Generate(entityType string, entityID uuid.UUID, title, current string) (string, error) {
+	if slug.Make(title) == "" && current != "" {
+		return current, nil
+	}
+	base := baseSlug(entityType, title)
+	if current != "" && isCandidate(current, base) && !s.reserved[current] {
+		return current, nil
+	}
+
+	for n := 1; ; n++ {
+		next := candidate(base, n)
+		if s.reserved[next] {
+			continue
+		}
+		taken, retired, err := s.repo.InUse(entityType, next, entityID)
+		if err != nil {
+			return "", err
+		}
+		if !taken && !retired {
+			return next, nil
+		}
+	}
+}
+
+// Record is called whenever an entity is saved with newSlug, previously
+// oldSlug (empty when it is new). A retired slug keeps pointing at the
+// entity; the new slug stops pointing at whatever entity used it before,
+// since it now names this one. Failures are logged and do not fail the
+// save.
+func (s *service) Record(entityType string, entityID uuid.UUID, oldSlug, newSlug string) {
+	if err := s.repo.DeleteSlug(entityType, newSlug); err != nil {
+		log.Printf("failed to release %s slug %q: %v", entityType, newSlug, err)
+	}
+	if oldSlug == "" || oldSlug == newSlug {
+		return
+	}
+	history := &History{EntityType: entityType, Slug: oldSlug, EntityID: entityID}
+	if err := s.repo.Save(history); err != nil {
+		log.Printf("failed to record %s slug %q: %v", entityType, oldSlug, err)
+	}
+}
+
+// Resolve returns the entity that used to have the slug, or nil.
+func (s *service) Resolve(entityType, slug string) (*uuid.UUID, error) {
+	history, err := s.repo.FindBySlug(entityType, slug)
+	if err != nil || history == nil {
+		return nil, err
+	}
+	return &history.EntityID, nil
+}
+
+// Forget drops the old slugs of a deleted entity, so they no longer
+// redirect and can be used again.
+func (s *service) Forget(entityType string, entityID uuid.UUID) {
+	if err := s.repo.DeleteByEntity(entityType, entityID); err != nil {
+		log.Printf("failed to delete %s slug history: %v", entityType, err)
+	}
+}
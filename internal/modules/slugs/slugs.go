@@ -0,0 +1,80 @@
+package slugs
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+// maxLength is the size of the slug columns.
+const maxLength = 255
+
+var (
+	ErrInvalidSlug  = errors.New("slug must be lowercase letters, digits and underscores separated by single hyphens")
+	ErrReservedSlug = errors.New("slug is reserved")
+	ErrSlugTaken    = errors.New("slug is already taken")
+)
+
+// reserved are slugs that name frontend or API routes next to the post and
+// project pages. SLUG_RESERVED_WORDS adds more.
+var reserved = []string{
+	"admin", "api", "archive", "atom", "category", "drafts", "edit", "feed",
+	"index", "login", "logout", "media", "new", "page", "robots", "rss",
+	"search", "sitemap", "sitemaps", "slug", "static", "tags",
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9_]+(-[a-z0-9_]+)*$`)
+
+// Validate checks a slug asked for by a client. It is not normalized: a
+// slug that is not already valid is rejected.
+func (s *service) Validate(requested string) error {
+	if len(requested) > maxLength || !slugPattern.MatchString(requested) {
+		return ErrInvalidSlug
+	}
+	if s.reserved[requested] {
+		return ErrReservedSlug
+	}
+	return nil
+}
+
+// candidate returns the n-th slug tried for base: base itself, then base-2,
+// base-3 and so on, shortened to fit the column.
+func candidate(base string, n int) string {
+	if n == 1 {
+		return truncate(base, maxLength)
+	}
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(base, maxLength-len(suffix)) + suffix
+}
+
+func truncate(base string, length int) string {
+	if len(base) <= length {
+		return base
+	}
+	return strings.TrimRight(base[:length], "-")
+}
+
+// isCandidate reports whether current is one of the slugs tried for base.
+func isCandidate(current, base string) bool {
+	if current == candidate(base, 1) {
+		return true
+	}
+	i := strings.LastIndex(current, "-")
+	if i < 0 {
+		return false
+	}
+	n, err := strconv.Atoi(current[i+1:])
+	return err == nil && n > 1 && current == candidate(base, n)
+}
+
+// baseSlug derives a slug from a title, falling back to the entity type for
+// titles without a letter or digit.
+func baseSlug(entityType, title string) string {
+	if base := slug.Make(title); base != "" {
+		return base
+	}
+	return entityType
+}
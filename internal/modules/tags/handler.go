@@ -0,0 +1,231 @@
+package tags
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func toSummaryResponse(summary *Summary) gin.H {
+	return gin.H{
+		"id":          summary.ID,
+		"name":        summary.Name,
+		"slug":        summary.Slug,
+		"description": summary.Description,
+		"post_count":  summary.PostCount,
+	}
+}
+
+func toTagResponse(tag *posts.Tag) gin.H {
+	return gin.H{
+		"id":          tag.ID,
+		"name":        tag.Name,
+		"slug":        tag.Slug,
+		"description": tag.Description,
+	}
+}
+
+func toSummaryList(summaries []Summary) []gin.H {
+	data := make([]gin.H, 0, len(summaries))
+	for i := range summaries {
+		data = append(data, toSummaryResponse(&summaries[i]))
+	}
+	return data
+}
+
+// GetPublicTags godoc
+// @Summary      Public - Get All Tags
+// @Description  Tags of published posts with the number of published posts using each, most used first
+// @Tags         Public - Tags
+// @Produce      json
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /public/tags [get]
+func (h *Handler) GetPublicTags(c *gin.Context) {
+	summaries, err := h.service.GetPublic()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch tags", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Tags fetched successfully", toSummaryList(summaries))
+}
+
+// GetPublicTagPosts godoc
+// @Summary      Public - Get Posts by Tag
+// @Description  A tag and its published posts, newest first. A tag without published posts is not found. A slug the tag had before it was renamed answers 301 with the current slug in data.slug and the canonical URL in the Location header.
+// @Tags         Public - Tags
+// @Produce      json
+// @Param        slug  path      string  true  "Tag slug"
+// @Success      200   {object}  map[string]interface{}
+// @Success      301   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /public/tags/{slug}/posts [get]
+func (h *Handler) GetPublicTagPosts(c *gin.Context) {
+	slug := c.Param("slug")
+	tag, published, err := h.service.GetPosts(slug)
+	if err != nil {
+		if !errors.Is(err, ErrTagNotFound) && !errors.Is(err, posts.ErrTagNotFound) {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch posts", err.Error())
+			return
+		}
+		current, err := h.service.ResolveSlug(slug)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to fetch posts", err.Error())
+			return
+		}
+		if current != "" {
+			c.Header("Location", "/api/public/tags/"+url.PathEscape(current)+"/posts")
+			response.Success(c, http.StatusMovedPermanently, "Tag moved", gin.H{"slug": current})
+			return
+		}
+		response.Error(c, http.StatusNotFound, "Tag not found", ErrTagNotFound.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Posts fetched successfully", gin.H{
+		"tag":   toTagResponse(tag),
+		"posts": published,
+	})
+}
+
+// GetAdminTags godoc
+// @Summary      Admin - Get All Tags
+// @Description  Every tag with the number of posts using it, drafts included. Tags with a count of 0 are unused.
+// @Tags         Admin - Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags [get]
+func (h *Handler) GetAdminTags(c *gin.Context) {
+	summaries, err := h.service.GetAdmin()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to fetch tags", err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, "Tags fetched successfully", toSummaryList(summaries))
+}
+
+// UpdateTag godoc
+// @Summary      Admin - Update Tag
+// @Description  Rename a tag and set its description. The slug follows the name; the old slug redirects to the tag with 301. Renaming to the name of another tag answers 409; merge the tags instead.
+// @Tags         Admin - Tags
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string            true  "Tag ID"
+// @Param        request  body  UpdateTagRequest  true  "Tag Data"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id} [put]
+func (h *Handler) UpdateTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	tag, err := h.service.Update(audit.ActorFromContext(c), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTagNotFound):
+			response.Error(c, http.StatusNotFound, "Tag not found", err.Error())
+		case errors.Is(err, ErrInvalidName):
+			response.Error(c, http.StatusBadRequest, "Invalid name", err.Error())
+		case errors.Is(err, ErrTagExists):
+			response.Error(c, http.StatusConflict, "Tag already exists", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to update tag", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusOK, "Tag updated successfully", toTagResponse(tag))
+}
+
+// MergeTags godoc
+// @Summary      Admin - Merge Tags
+// @Description  Move every post of the source tags to this tag and delete the sources, in one transaction
+// @Tags         Admin - Tags
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string            true  "Target tag ID"
+// @Param        request  body  MergeTagsRequest  true  "Tags to merge into the target"
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/{id}/merge [post]
+func (h *Handler) MergeTags(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid ID", "invalid id")
+		return
+	}
+
+	var req MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	tag, err := h.service.Merge(audit.ActorFromContext(c), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTagNotFound):
+			response.Error(c, http.StatusNotFound, "Tag not found", err.Error())
+		case errors.Is(err, ErrInvalidMerge), errors.Is(err, ErrSourcesNeeded):
+			response.Error(c, http.StatusBadRequest, "Invalid merge", err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to merge tags", err.Error())
+		}
+		return
+	}
+	response.Success(c, http.StatusOK, "Tags merged successfully", toTagResponse(tag))
+}
+
+// DeleteUnusedTags godoc
+// @Summary      Admin - Delete Unused Tags
+// @Description  Delete every tag that no post uses and return the deleted tags
+// @Tags         Admin - Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/tags/unused [delete]
+func (h *Handler) DeleteUnusedTags(c *gin.Context) {
+	deleted, err := h.service.DeleteUnused(audit.ActorFromContext(c))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to delete tags", err.Error())
+		return
+	}
+
+	data := make([]gin.H, 0, len(deleted))
+	for i := range deleted {
+		data = append(data, toTagResponse(&deleted[i]))
+	}
+	response.Success(c, http.StatusOK, "Unused tags deleted successfully", data)
+}
@@ -0,0 +1,13 @@
+package tags
+
+import "github.com/google/uuid"
+
+// Summary is a tag with the number of posts that use it. In the public API
+// only published posts are counted.
+type Summary struct {
+	ID          uuid.UUID
+	Name        string
+	Slug        string
+	Description string
+	PostCount   int64
+}
@@ -0,0 +1,119 @@
+package tags
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	FindAll(publishedOnly bool) ([]Summary, error)
+	FindByID(id uuid.UUID) (*posts.Tag, error)
+	FindByIDs(ids []uuid.UUID) ([]posts.Tag, error)
+	FindBySlug(slug string) (*posts.Tag, error)
+	FindConflict(name, slug string, exceptID uuid.UUID) (*posts.Tag, error)
+	Update(tag *posts.Tag) error
+	Merge(sourceIDs []uuid.UUID, targetID uuid.UUID) error
+	DeleteUnused() ([]posts.Tag, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// visiblePosts matches posts that are public at @now, as in the public post
+// listings.
+const visiblePosts = "(posts.is_published OR posts.publish_at <= @now) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > @now)"
+
+// FindAll returns every tag with its post count, most used first. With
+// publishedOnly, only published posts are counted and tags without any are
+// left out, so drafts do not reveal their tags.
+func (r *repository) FindAll(publishedOnly bool) ([]Summary, error) {
+	join := "LEFT JOIN post_tags ON post_tags.tag_id = tags.id"
+	count := "COUNT(post_tags.post_id)"
+	if publishedOnly {
+		join = "JOIN post_tags ON post_tags.tag_id = tags.id JOIN posts ON posts.id = post_tags.post_id AND " + visiblePosts
+		count = "COUNT(posts.id)"
+	}
+
+	var summaries []Summary
+	err := r.db.Raw(`
+		SELECT tags.id, tags.name, tags.slug, COALESCE(tags.description, '') AS description, `+count+` AS post_count
+		FROM tags `+join+`
+		GROUP BY tags.id
+		ORDER BY post_count DESC, tags.name`,
+		map[string]interface{}{"now": time.Now()}).Scan(&summaries).Error
+	return summaries, err
+}
+
+func (r *repository) FindByID(id uuid.UUID) (*posts.Tag, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *repository) FindByIDs(ids []uuid.UUID) ([]posts.Tag, error) {
+	var tags []posts.Tag
+	err := r.db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
+
+func (r *repository) FindBySlug(slug string) (*posts.Tag, error) {
+	return r.findOne("slug = ?", slug)
+}
+
+// FindConflict returns another tag with the name, in any letter case, or
+// with the slug.
+func (r *repository) FindConflict(name, slug string, exceptID uuid.UUID) (*posts.Tag, error) {
+	return r.findOne("(LOWER(name) = LOWER(?) OR slug = ?) AND id <> ?", name, slug, exceptID)
+}
+
+func (r *repository) findOne(query string, args ...interface{}) (*posts.Tag, error) {
+	var tag posts.Tag
+	err := r.db.Where(query, args...).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *repository) Update(tag *posts.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Merge moves the posts of the source tags to the target and deletes the
+// sources, all in one transaction. A post that already has the target keeps
+// a single link to it.
+func (r *repository) Merge(sourceIDs []uuid.UUID, targetID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO post_tags (post_id, tag_id)
+			SELECT DISTINCT post_id, ? FROM post_tags WHERE tag_id IN ?
+			ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_tags WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&posts.Tag{}, "id IN ?", sourceIDs).Error
+	})
+}
+
+// DeleteUnused deletes the tags no post uses and returns them.
+func (r *repository) DeleteUnused() ([]posts.Tag, error) {
+	var deleted []posts.Tag
+	err := r.db.Clauses(clause.Returning{}).
+		Where("NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id)").
+		Delete(&deleted).Error
+	return deleted, err
+}
@@ -0,0 +1,197 @@
+package tags
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
+	"github.com/prakoso-id/personal-backend/internal/modules/posts"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+)
+
+var (
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrInvalidName   = errors.New("tag name must contain a letter or digit")
+	ErrTagExists     = errors.New("another tag has this name; merge the tags instead")
+	ErrInvalidMerge  = errors.New("source_ids must list other existing tags")
+	ErrSourcesNeeded = errors.New("source_ids must not be empty")
+)
+
+type Service interface {
+	GetPublic() ([]Summary, error)
+	GetAdmin() ([]Summary, error)
+	GetPosts(slug string) (*posts.Tag, []posts.Post, error)
+	ResolveSlug(oldSlug string) (string, error)
+	Update(actor audit.Actor, id uuid.UUID, req *UpdateTagRequest) (*posts.Tag, error)
+	Merge(actor audit.Actor, targetID uuid.UUID, req *MergeTagsRequest) (*posts.Tag, error)
+	DeleteUnused(actor audit.Actor) ([]posts.Tag, error)
+}
+
+type service struct {
+	repo  Repository
+	posts posts.Service
+	audit audit.Service
+	slugs slugs.Service
+}
+
+func NewService(repo Repository, postService posts.Service, auditService audit.Service, slugService slugs.Service) Service {
+	return &service{repo: repo, posts: postService, audit: auditService, slugs: slugService}
+}
+
+type UpdateTagRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []string `json:"source_ids" binding:"required"` // tags merged into this one, then deleted
+}
+
+// GetPublic returns the tags of published posts with their counts.
+func (s *service) GetPublic() ([]Summary, error) {
+	return s.repo.FindAll(true)
+}
+
+// GetAdmin returns every tag, counting all posts, including unused tags.
+func (s *service) GetAdmin() ([]Summary, error) {
+	return s.repo.FindAll(false)
+}
+
+// GetPosts returns a tag and its published posts, newest first. A tag
+// without published posts is not found, so drafts do not reveal their tags.
+func (s *service) GetPosts(slug string) (*posts.Tag, []posts.Post, error) {
+	tag, err := s.repo.FindBySlug(slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag == nil {
+		return nil, nil, ErrTagNotFound
+	}
+
+	published, err := s.posts.GetPublished(slug, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(published) == 0 {
+		return nil, nil, ErrTagNotFound
+	}
+	return tag, published, nil
+}
+
+// ResolveSlug returns the current slug of the tag with published posts that
+// used to be at oldSlug, or an empty string when there is none.
+func (s *service) ResolveSlug(oldSlug string) (string, error) {
+	id, err := s.slugs.Resolve(slugs.EntityTag, oldSlug)
+	if err != nil || id == nil {
+		return "", err
+	}
+	tag, err := s.repo.FindByID(*id)
+	if err != nil || tag == nil {
+		return "", err
+	}
+	published, err := s.posts.GetPublished(tag.Slug, 1)
+	if err != nil || len(published) == 0 {
+		return "", err
+	}
+	return tag.Slug, nil
+}
+
+// Update renames a tag and sets its description. The slug follows the name;
+// the old one keeps redirecting to the tag. Renaming to the name of another
+// tag is refused; merging does that.
+func (s *service) Update(actor audit.Actor, id uuid.UUID, req *UpdateTagRequest) (*posts.Tag, error) {
+	tag, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	before := *tag
+
+	name := strings.TrimSpace(req.Name)
+	tagSlug := slug.Make(name)
+	if tagSlug == "" {
+		return nil, ErrInvalidName
+	}
+	conflict, err := s.repo.FindConflict(name, tagSlug, id)
+	if err != nil {
+		return nil, err
+	}
+	if conflict != nil {
+		return nil, ErrTagExists
+	}
+
+	tag.Name = name
+	tag.Slug = tagSlug
+	tag.Description = req.Description
+	if err := s.repo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(actor, audit.ActionUpdate, "tag", tag.ID.String(), before, tag)
+	s.slugs.Record(slugs.EntityTag, tag.ID, before.Slug, tag.Slug)
+	return tag, nil
+}
+
+// Merge moves every post of the source tags to the target tag and deletes
+// the sources.
+func (s *service) Merge(actor audit.Actor, targetID uuid.UUID, req *MergeTagsRequest) (*posts.Tag, error) {
+	target, err := s.repo.FindByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrTagNotFound
+	}
+
+	if len(req.SourceIDs) == 0 {
+		return nil, ErrSourcesNeeded
+	}
+	seen := make(map[uuid.UUID]bool, len(req.SourceIDs))
+	var sourceIDs []uuid.UUID
+	for _, idStr := range req.SourceIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil || id == targetID {
+			return nil, ErrInvalidMerge
+		}
+		if !seen[id] {
+			seen[id] = true
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+
+	sources, err := s.repo.FindByIDs(sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) != len(sourceIDs) {
+		return nil, ErrInvalidMerge
+	}
+
+	if err := s.repo.Merge(sourceIDs, targetID); err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		s.audit.Record(actor, audit.ActionMerge, "tag", source.ID.String(), source,
+			map[string]interface{}{"MergedInto": target.ID.String()})
+		s.slugs.Forget(slugs.EntityTag, source.ID)
+	}
+	return target, nil
+}
+
+// DeleteUnused deletes the tags that no post uses any more.
+func (s *service) DeleteUnused(actor audit.Actor) ([]posts.Tag, error) {
+	deleted, err := s.repo.DeleteUnused()
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range deleted {
+		s.audit.Record(actor, audit.ActionDelete, "tag", tag.ID.String(), tag, nil)
+		s.slugs.Forget(slugs.EntityTag, tag.ID)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,50 @@
+package netpolicy
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prakoso-id/personal-backend/internal/utils/response"
+)
+
+type Handler struct {
+	policy *Policy
+}
+
+func NewHandler(policy *Policy) *Handler {
+	return &Handler{policy: policy}
+}
+
+// GetNetworkPolicy godoc
+// @Summary      Admin - Get Network Policy
+// @Description  Shows how many networks are on each list and how many admin requests were denied since the server started, by reason (denied, not_allowed, sensitive, invalid_address)
+// @Tags         Admin - Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]string
+// @Router       /admin/network-policy [get]
+func (h *Handler) GetNetworkPolicy(c *gin.Context) {
+	stats := h.policy.Stats()
+
+	var last gin.H
+	if stats.Last != nil {
+		last = gin.H{
+			"ip":     stats.Last.IP,
+			"method": stats.Last.Method,
+			"path":   stats.Last.Path,
+			"reason": stats.Last.Reason,
+			"at":     stats.Last.At,
+		}
+	}
+
+	response.Success(c, http.StatusOK, "Network policy fetched successfully", gin.H{
+		"allow_count":           stats.AllowCount,
+		"deny_count":            stats.DenyCount,
+		"sensitive_allow_count": stats.SensitiveAllowCount,
+		"trusted_proxy_count":   stats.TrustedProxyCount,
+		"denials":               stats.Denials,
+		"total_denials":         stats.Total,
+		"last_denial":           last,
+	})
+}
@@ -0,0 +1,190 @@
+// Package netpolicy decides which client networks may use the protected
+// admin API and counts the requests it turns away.
+package netpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+// Reasons a request is denied
+const (
+	ReasonDenied         = "denied"          // on the deny list
+	ReasonNotAllowed     = "not_allowed"     // not on the allow list
+	ReasonSensitive      = "sensitive"       // sensitive route from outside SensitiveAllow
+	ReasonInvalidAddress = "invalid_address" // client address could not be parsed
+)
+
+// Policy holds the parsed network lists. A nil list matches nothing; an
+// empty allow list means every network is allowed.
+type Policy struct {
+	TrustedProxies []string
+	allow          []netip.Prefix
+	deny           []netip.Prefix
+	sensitiveAllow []netip.Prefix
+
+	mu       sync.Mutex
+	denials  map[string]int64
+	lastDeny *Denial
+}
+
+// Denial describes the most recent request that was turned away.
+type Denial struct {
+	IP     string
+	Method string
+	Path   string
+	Reason string
+	At     time.Time
+}
+
+// Stats summarises the policy and the denials since the server started.
+type Stats struct {
+	AllowCount          int
+	DenyCount           int
+	SensitiveAllowCount int
+	TrustedProxyCount   int
+	Denials             map[string]int64
+	Total               int64
+	Last                *Denial
+}
+
+// New parses the configured lists and fails on any entry that is neither an
+// IP address nor a CIDR range.
+func New(cfg config.NetworkConfig) (*Policy, error) {
+	p := &Policy{denials: make(map[string]int64)}
+
+	var err error
+	if p.allow, err = parseList("ADMIN_ALLOW_CIDRS", cfg.AdminAllow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = parseList("ADMIN_DENY_CIDRS", cfg.AdminDeny); err != nil {
+		return nil, err
+	}
+	if p.sensitiveAllow, err = parseList("ADMIN_SENSITIVE_ALLOW_CIDRS", cfg.SensitiveAllow); err != nil {
+		return nil, err
+	}
+
+	proxies, err := parseList("TRUSTED_PROXIES", cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	for _, prefix := range proxies {
+		p.TrustedProxies = append(p.TrustedProxies, prefix.String())
+	}
+
+	return p, nil
+}
+
+func parseList(name, value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid CIDR %q", name, item)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid IP address %q", name, item)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAdmin returns the reason the client IP may not use the admin API, or
+// an empty string if it may.
+func (p *Policy) CheckAdmin(ip string) string {
+	if len(p.allow) == 0 && len(p.deny) == 0 {
+		return ""
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ReasonInvalidAddress
+	}
+	addr = addr.Unmap()
+
+	if contains(p.deny, addr) {
+		return ReasonDenied
+	}
+	if len(p.allow) > 0 && !contains(p.allow, addr) {
+		return ReasonNotAllowed
+	}
+	return ""
+}
+
+// CheckSensitive is like CheckAdmin for routes that change credentials. They
+// additionally need a network from SensitiveAllow when that list is set.
+func (p *Policy) CheckSensitive(ip string) string {
+	if len(p.sensitiveAllow) == 0 {
+		return ""
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ReasonInvalidAddress
+	}
+	if !contains(p.sensitiveAllow, addr.Unmap()) {
+		return ReasonSensitive
+	}
+	return ""
+}
+
+// RecordDenial counts a denied request and returns the number of denials for
+// the same reason so far.
+func (p *Policy) RecordDenial(denial Denial) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.denials[denial.Reason]++
+	p.lastDeny = &denial
+	return p.denials[denial.Reason]
+}
+
+// Stats returns a snapshot of the configuration sizes and denial counters.
+func (p *Policy) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := Stats{
+		AllowCount:          len(p.allow),
+		DenyCount:           len(p.deny),
+		SensitiveAllowCount: len(p.sensitiveAllow),
+		TrustedProxyCount:   len(p.TrustedProxies),
+		Denials:             make(map[string]int64, len(p.denials)),
+	}
+	for reason, count := range p.denials {
+		stats.Denials[reason] = count
+		stats.Total += count
+	}
+	if p.lastDeny != nil {
+		last := *p.lastDeny
+		stats.Last = &last
+	}
+	return stats
+}
@@ -0,0 +1,157 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/prakoso-id/personal-backend/internal/config"
+)
+
+// PolicyError lists every rule a password breaks, in a form that can be shown
+// to the user.
+type PolicyError struct {
+	Problems []string
+}
+
+func (e *PolicyError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Policy checks new passwords against the configured length and character
+// class rules and, optionally, a local list of breached passwords.
+type Policy struct {
+	cfg      config.PasswordConfig
+	breached *BreachedList
+}
+
+func NewPolicy(cfg config.PasswordConfig) *Policy {
+	p := &Policy{cfg: cfg}
+	if cfg.BreachedListPath != "" {
+		p.breached = &BreachedList{path: cfg.BreachedListPath}
+	}
+	return p
+}
+
+// Validate returns a *PolicyError when password breaks one or more rules. Any
+// other error means the breached-password list could not be read.
+func (p *Policy) Validate(password string) error {
+	var problems []string
+
+	if len([]rune(password)) < p.cfg.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.cfg.RequireUpper && !hasUpper {
+		problems = append(problems, "must contain an uppercase letter")
+	}
+	if p.cfg.RequireLower && !hasLower {
+		problems = append(problems, "must contain a lowercase letter")
+	}
+	if p.cfg.RequireDigit && !hasDigit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !hasSymbol {
+		problems = append(problems, "must contain a symbol")
+	}
+
+	if p.breached != nil {
+		count, err := p.breached.Count(password)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			problems = append(problems, "appears in a list of breached passwords")
+		}
+	}
+
+	if len(problems) > 0 {
+		for i := range problems {
+			problems[i] = "password " + problems[i]
+		}
+		return &PolicyError{Problems: problems}
+	}
+	return nil
+}
+
+// BreachedList looks up passwords in a local copy of a k-anonymity breached
+// password corpus such as Have I Been Pwned. Passwords are identified by the
+// uppercase hex SHA-1 hash. The path is either a directory of range files
+// named after the first five hash characters (<PREFIX> or <PREFIX>.txt, one
+// "SUFFIX:COUNT" per line, as served by the range API), or a single file of
+// "HASH:COUNT" lines sorted by hash.
+type BreachedList struct {
+	path string
+}
+
+// Count returns how often the password appears in the list, 0 if not at all.
+func (b *BreachedList) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	info, err := os.Stat(b.path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return scanHashFile(b.path, hash)
+	}
+
+	prefix, suffix := hash[:5], hash[5:]
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		count, err := scanHashFile(filepath.Join(b.path, name), suffix)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return count, err
+	}
+	return 0, nil
+}
+
+// scanHashFile searches a sorted "HASH:COUNT" file for hash, stopping as soon
+// as the sort order has passed it.
+func scanHashFile(path, hash string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		entry, countStr, _ := strings.Cut(line, ":")
+		entry = strings.ToUpper(entry)
+
+		if entry == hash {
+			var count int
+			if _, err := fmt.Sscanf(countStr, "%d", &count); err != nil || count < 1 {
+				count = 1
+			}
+			return count, nil
+		}
+		if entry > hash {
+			break
+		}
+	}
+	return 0, scanner.Err()
+}
@@ -1,17 +1,33 @@
 package routes
 
 import (	
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"github.com/prakoso-id/personal-backend/internal/config"
+	"github.com/prakoso-id/personal-backend/internal/indexnow"
+	"github.com/prakoso-id/personal-backend/internal/keyring"
+	"github.com/prakoso-id/personal-backend/internal/mailer"
 	"github.com/prakoso-id/personal-backend/internal/middleware"
+	"github.com/prakoso-id/personal-backend/internal/modules/audit"
 	"github.com/prakoso-id/personal-backend/internal/modules/auth"
 	"github.com/prakoso-id/personal-backend/internal/modules/contact"
 	"github.com/prakoso-id/personal-backend/internal/modules/images"
 	"github.com/prakoso-id/personal-backend/internal/modules/experiences"
+	"github.com/prakoso-id/personal-backend/internal/modules/feeds"
 	"github.com/prakoso-id/personal-backend/internal/modules/posts"
 	"github.com/prakoso-id/personal-backend/internal/modules/profiles"
 	"github.com/prakoso-id/personal-backend/internal/modules/projects"
+	"github.com/prakoso-id/personal-backend/internal/modules/revisions"
+	"github.com/prakoso-id/personal-backend/internal/modules/schedule"
+	"github.com/prakoso-id/personal-backend/internal/modules/search"
+	"github.com/prakoso-id/personal-backend/internal/modules/seo"
+	"github.com/prakoso-id/personal-backend/internal/modules/series"
 	"github.com/prakoso-id/personal-backend/internal/modules/skills"
+	"github.com/prakoso-id/personal-backend/internal/modules/slugs"
+	"github.com/prakoso-id/personal-backend/internal/modules/tags"
+	"github.com/prakoso-id/personal-backend/internal/netpolicy"
+	"github.com/prakoso-id/personal-backend/internal/scheduler"
     
     // Swagger
     "github.com/prakoso-id/personal-backend/docs"
@@ -21,40 +37,84 @@ import (
 	"gorm.io/gorm"
 )
 
-func RegisterRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+// RegisterRoutes wires the modules and their routes into r. It returns the
+// background jobs of the modules, which the caller runs with the scheduler.
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, keys *keyring.Keyring, policy *netpolicy.Policy) []scheduler.Job {
+	// Only believe X-Forwarded-For from our own proxies. Login throttling,
+	// sessions and the audit log key on the client IP, so this is set here,
+	// with the routes, rather than left to whoever builds the engine.
+	r.RemoteIPHeaders = []string{"X-Forwarded-For"}
+	if err := r.SetTrustedProxies(policy.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+
 	// Initialize base URL for image paths
 	images.SetBaseURL(cfg.Server.BaseURL)
 	// Initialize base URL for profile file paths (avatar, resume)
 	profiles.SetBaseURL(cfg.Server.BaseURL)
+	// Initialize base URL for series cover paths
+	series.SetBaseURL(cfg.Server.BaseURL)
 
 	// Swagger Info
 	docs.SwaggerInfo.BasePath = "/api"
 
 	// Repositories
+	auditRepo := audit.NewRepository(db)
 	authRepo := auth.NewRepository(db)
 	imageRepo := images.NewRepository(db)
 	profileRepo := profiles.NewRepository(db)
 	postRepo := posts.NewRepository(db)
 	projectRepo := projects.NewRepository(db)
 	experienceRepo := experiences.NewRepository(db)
+	skillRepo := skills.NewRepository(db)
+	searchRepo := search.NewRepository(db)
+	revisionRepo := revisions.NewRepository(db)
+	slugRepo := slugs.NewRepository(db)
+	tagRepo := tags.NewRepository(db)
+	seriesRepo := series.NewRepository(db)
+
+	// Mail
+	mail := mailer.New(cfg)
+
+	// Search engine notifications
+	indexNow := indexnow.New(cfg)
 
 	// Services
-	authService := auth.NewService(authRepo, cfg)
-	imageService := images.NewService(imageRepo)
-	profileService := profiles.NewService(profileRepo)
-	postService := posts.NewService(postRepo, imageRepo)
-	projectService := projects.NewService(projectRepo, imageRepo)
-	experienceService := experiences.NewService(experienceRepo)
+	auditService := audit.NewService(auditRepo, cfg)
+	revisionService := revisions.NewService(revisionRepo)
+	slugService := slugs.NewService(slugRepo, cfg)
+	authService := auth.NewService(authRepo, cfg, mail, keys, auditService)
+	imageService := images.NewService(imageRepo, auditService)
+	profileService := profiles.NewService(profileRepo, auditService, revisionService)
+	postService := posts.NewService(postRepo, imageRepo, auditService, revisionService, slugService, cfg.Site, indexNow)
+	projectService := projects.NewService(projectRepo, imageRepo, auditService, revisionService, slugService, cfg.Site, indexNow)
+	experienceService := experiences.NewService(experienceRepo, auditService, revisionService)
+	skillService := skills.NewService(skillRepo, auditService)
+	scheduleService := schedule.NewService(postService, projectService)
+	searchService := search.NewService(searchRepo)
+	tagService := tags.NewService(tagRepo, postService, auditService, slugService)
+	feedService := feeds.NewService(postService, tagService, cfg)
+	seriesService := series.NewService(seriesRepo, auditService, slugService)
+	seoService := seo.NewService(postService, projectService, profileService, cfg)
 
 	// Handlers
+	auditHandler := audit.NewHandler(auditService)
+	revisionHandler := revisions.NewHandler(revisionService)
 	authHandler := auth.NewHandler(authService)
 	imageHandler := images.NewHandler(imageService)
 	profileHandler := profiles.NewHandler(profileService)
 	postHandler := posts.NewHandler(postService)
 	projectHandler := projects.NewHandler(projectService)
-	skillHandler := skills.NewHandler(db)
+	skillHandler := skills.NewHandler(skillService)
 	contactHandler := contact.NewHandler(db)
 	experienceHandler := experiences.NewHandler(experienceService, profileService)
+	networkHandler := netpolicy.NewHandler(policy)
+	scheduleHandler := schedule.NewHandler(scheduleService, cfg.Server.BaseURL)
+	searchHandler := search.NewHandler(searchService)
+	feedHandler := feeds.NewHandler(feedService, cfg.Server.BaseURL, cfg.Feed.FullContent)
+	seoHandler := seo.NewHandler(seoService)
+	tagHandler := tags.NewHandler(tagService)
+	seriesHandler := series.NewHandler(seriesService)
 
 	api := r.Group("/api")
 	{
@@ -65,9 +125,14 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			public.GET("/skills", skillHandler.GetAll)
 			public.GET("/posts", postHandler.GetPublicPosts)
 			public.GET("/posts/:slug", postHandler.GetPublicPostBySlug)
+			public.GET("/tags", tagHandler.GetPublicTags)
+			public.GET("/tags/:slug/posts", tagHandler.GetPublicTagPosts)
+			public.GET("/series/:slug", seriesHandler.GetPublicSeries)
 			public.GET("/projects", projectHandler.GetPublicProjects)
 			public.GET("/projects/:id", projectHandler.GetPublicProjectByID)
+			public.GET("/projects/slug/:slug", projectHandler.GetPublicProjectBySlug)
 			public.GET("/experiences", experienceHandler.GetPublicExperiences)
+			public.GET("/search", searchHandler.Search)
 			public.POST("/contact", contactHandler.CreateMessage)
 
 			// Swagger
@@ -77,53 +142,181 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		// Admin Routes (Protected)
 		admin := api.Group("/admin")
 		admin.POST("/login", authHandler.Login)
+		admin.POST("/login/2fa", authHandler.LoginTwoFactor)
+		admin.POST("/login/magic-link", authHandler.RequestMagicLink)
+		admin.POST("/login/magic-link/verify", authHandler.VerifyMagicLink)
+		admin.POST("/login/passkey/begin", authHandler.PasskeyLoginBegin)
+		admin.POST("/login/passkey/finish", authHandler.PasskeyLoginFinish)
+		admin.GET("/oidc/authorize", authHandler.OIDCAuthorize)
+		admin.POST("/oidc/callback", authHandler.OIDCCallback)
+		admin.POST("/refresh", authHandler.Refresh)
+		admin.POST("/logout", authHandler.Logout)
+		admin.POST("/forgot-password", authHandler.ForgotPassword)
+		admin.POST("/reset-password", authHandler.ResetPassword)
 		
+		// Resource routes require a permission of the caller's role (or API key
+		// scopes); account self-service routes need a user login
+		can := middleware.RequirePermission
+
+		// Credential changes may be limited to fewer networks than the rest
+		sensitive := middleware.RequireSensitiveNetwork(policy)
+
 		protected := admin.Group("/")
-		protected.Use(middleware.AuthMiddleware(cfg))
+		protected.Use(middleware.NetworkPolicy(policy), middleware.AuthMiddleware(keys, authService))
 		{
 			// Profile (Admin)
-			protected.GET("/profile", profileHandler.GetProfile)
-			protected.PUT("/profile", profileHandler.UpdateProfile)
+			protected.GET("/profile", can(auth.PermProfileRead), profileHandler.GetProfile)
+			protected.PUT("/profile", can(auth.PermProfileWrite), profileHandler.UpdateProfile)
+			protected.GET("/profile/:id/revisions", can(auth.PermProfileRead), revisionHandler.List(revisions.EntityProfile))
+			protected.GET("/profile/:id/revisions/diff", can(auth.PermProfileRead), revisionHandler.Diff(revisions.EntityProfile))
+			protected.GET("/profile/:id/revisions/:number", can(auth.PermProfileRead), revisionHandler.Get(revisions.EntityProfile))
+			protected.POST("/profile/:id/revisions/:number/restore", can(auth.PermProfileWrite), profileHandler.RestoreProfileRevision)
+			protected.GET("/profile/:id/autosave", can(auth.PermProfileWrite), revisionHandler.GetDraft(revisions.EntityProfile))
+			protected.PUT("/profile/:id/autosave", can(auth.PermProfileWrite), revisionHandler.SaveDraft(revisions.EntityProfile, profiles.UpdateProfileRequest{}))
+			protected.DELETE("/profile/:id/autosave", can(auth.PermProfileWrite), revisionHandler.DeleteDraft(revisions.EntityProfile))
+
+			account := protected.Group("/")
+			account.Use(middleware.RequireUserToken())
+			{
+				// Auth Updates
+				account.PUT("/update-email", sensitive, authHandler.UpdateEmail)
+				account.PUT("/update-password", sensitive, authHandler.UpdatePassword)
+
+				// Two-Factor Authentication
+				account.POST("/2fa/setup", sensitive, authHandler.SetupTOTP)
+				account.POST("/2fa/enable", sensitive, authHandler.EnableTOTP)
+				account.POST("/2fa/disable", sensitive, authHandler.DisableTOTP)
+				account.POST("/2fa/recovery-codes", sensitive, authHandler.RegenerateRecoveryCodes)
+
+				// Passkeys
+				account.GET("/passkeys", authHandler.GetPasskeys)
+				account.POST("/passkeys/register/begin", sensitive, authHandler.BeginPasskeyRegistration)
+				account.POST("/passkeys/register/finish", sensitive, authHandler.FinishPasskeyRegistration)
+				account.DELETE("/passkeys/:id", authHandler.DeletePasskey)
+
+				// Sessions
+				account.GET("/sessions", authHandler.GetSessions)
+				account.DELETE("/sessions", authHandler.RevokeAllSessions)
+				account.DELETE("/sessions/:id", authHandler.RevokeSession)
+
+				// API Keys (a key cannot be used to mint further keys)
+				account.GET("/api-keys", can(auth.PermAPIKeysManage), authHandler.GetAPIKeys)
+				account.POST("/api-keys", sensitive, can(auth.PermAPIKeysManage), authHandler.CreateAPIKey)
+				account.DELETE("/api-keys/:id", can(auth.PermAPIKeysManage), authHandler.RevokeAPIKey)
+			}
 
-			// Auth Updates
-			protected.PUT("/update-email", authHandler.UpdateEmail)
-			protected.PUT("/update-password", authHandler.UpdatePassword)
+			// Login Lockouts
+			protected.GET("/login-lockouts", can(auth.PermSecurityManage), authHandler.GetLoginLockouts)
+			protected.DELETE("/login-lockouts/:id", can(auth.PermSecurityManage), authHandler.ClearLoginLockout)
+
+			// Network Policy
+			protected.GET("/network-policy", can(auth.PermSecurityManage), networkHandler.GetNetworkPolicy)
+
+			// Audit Log
+			protected.GET("/audit", can(auth.PermAuditRead), auditHandler.GetAuditLogs)
+
+			// Users
+			protected.GET("/users", can(auth.PermUsersManage), authHandler.GetUsers)
+			protected.POST("/users", can(auth.PermUsersManage), authHandler.InviteUser)
+			protected.PUT("/users/:id/role", can(auth.PermUsersManage), authHandler.UpdateUserRole)
+			protected.DELETE("/users/:id", can(auth.PermUsersManage), authHandler.DeleteUser)
 
 			// Posts (Admin)
-			protected.GET("/posts", postHandler.GetAdminPosts)
-			protected.POST("/posts", postHandler.CreatePost)
-			protected.PUT("/posts/:id", postHandler.UpdatePost)
-			protected.DELETE("/posts/:id", postHandler.DeletePost)
+			protected.GET("/posts", can(auth.PermPostsRead), postHandler.GetAdminPosts)
+			protected.POST("/posts", can(auth.PermPostsWrite), postHandler.CreatePost)
+			protected.PUT("/posts/:id", can(auth.PermPostsWrite), postHandler.UpdatePost)
+			protected.DELETE("/posts/:id", can(auth.PermPostsWrite), postHandler.DeletePost)
+			protected.GET("/posts/:id/revisions", can(auth.PermPostsRead), revisionHandler.List(revisions.EntityPost))
+			protected.GET("/posts/:id/revisions/diff", can(auth.PermPostsRead), revisionHandler.Diff(revisions.EntityPost))
+			protected.GET("/posts/:id/revisions/:number", can(auth.PermPostsRead), revisionHandler.Get(revisions.EntityPost))
+			protected.POST("/posts/:id/revisions/:number/restore", can(auth.PermPostsWrite), postHandler.RestorePostRevision)
+			protected.GET("/posts/:id/autosave", can(auth.PermPostsWrite), revisionHandler.GetDraft(revisions.EntityPost))
+			protected.PUT("/posts/:id/autosave", can(auth.PermPostsWrite), revisionHandler.SaveDraft(revisions.EntityPost, posts.UpdatePostRequest{}))
+			protected.DELETE("/posts/:id/autosave", can(auth.PermPostsWrite), revisionHandler.DeleteDraft(revisions.EntityPost))
+
+			// Tags
+			protected.GET("/tags", can(auth.PermPostsRead), tagHandler.GetAdminTags)
+			protected.PUT("/tags/:id", can(auth.PermPostsWrite), tagHandler.UpdateTag)
+			protected.POST("/tags/:id/merge", can(auth.PermPostsWrite), tagHandler.MergeTags)
+			protected.DELETE("/tags/unused", can(auth.PermPostsWrite), tagHandler.DeleteUnusedTags)
+
+			// Series
+			protected.GET("/series", can(auth.PermPostsRead), seriesHandler.GetAdminSeries)
+			protected.POST("/series", can(auth.PermPostsWrite), seriesHandler.CreateSeries)
+			protected.GET("/series/:id", can(auth.PermPostsRead), seriesHandler.GetAdminSeriesByID)
+			protected.PUT("/series/:id", can(auth.PermPostsWrite), seriesHandler.UpdateSeries)
+			protected.DELETE("/series/:id", can(auth.PermPostsWrite), seriesHandler.DeleteSeries)
+			protected.PUT("/series/:id/posts", can(auth.PermPostsWrite), seriesHandler.SetSeriesPosts)
+
+			// Publishing Schedule
+			protected.GET("/schedule", can(auth.PermPostsRead), can(auth.PermProjectsRead), scheduleHandler.GetSchedule)
+			protected.GET("/schedule.ics", can(auth.PermPostsRead), can(auth.PermProjectsRead), scheduleHandler.GetScheduleICal)
 
 			// Projects (Admin)
-			protected.GET("/projects", projectHandler.GetAdminProjects)
-			protected.POST("/projects", projectHandler.CreateProject)
-			protected.PUT("/projects/:id", projectHandler.UpdateProject)
-			protected.DELETE("/projects/:id", projectHandler.Delete)
+			protected.GET("/projects", can(auth.PermProjectsRead), projectHandler.GetAdminProjects)
+			protected.POST("/projects", can(auth.PermProjectsWrite), projectHandler.CreateProject)
+			protected.PUT("/projects/:id", can(auth.PermProjectsWrite), projectHandler.UpdateProject)
+			protected.DELETE("/projects/:id", can(auth.PermProjectsWrite), projectHandler.Delete)
+			protected.GET("/projects/:id/revisions", can(auth.PermProjectsRead), revisionHandler.List(revisions.EntityProject))
+			protected.GET("/projects/:id/revisions/diff", can(auth.PermProjectsRead), revisionHandler.Diff(revisions.EntityProject))
+			protected.GET("/projects/:id/revisions/:number", can(auth.PermProjectsRead), revisionHandler.Get(revisions.EntityProject))
+			protected.POST("/projects/:id/revisions/:number/restore", can(auth.PermProjectsWrite), projectHandler.RestoreProjectRevision)
+			protected.GET("/projects/:id/autosave", can(auth.PermProjectsWrite), revisionHandler.GetDraft(revisions.EntityProject))
+			protected.PUT("/projects/:id/autosave", can(auth.PermProjectsWrite), revisionHandler.SaveDraft(revisions.EntityProject, projects.UpdateProjectRequest{}))
+			protected.DELETE("/projects/:id/autosave", can(auth.PermProjectsWrite), revisionHandler.DeleteDraft(revisions.EntityProject))
 
 			// Skills (Admin)
-			protected.GET("/skills", skillHandler.GetAll)
-			protected.POST("/skills", skillHandler.Create)
-			protected.PUT("/skills/:id", skillHandler.Update)
-			protected.DELETE("/skills/:id", skillHandler.Delete)
+			protected.GET("/skills", can(auth.PermSkillsRead), skillHandler.GetAll)
+			protected.POST("/skills", can(auth.PermSkillsWrite), skillHandler.Create)
+			protected.PUT("/skills/:id", can(auth.PermSkillsWrite), skillHandler.Update)
+			protected.DELETE("/skills/:id", can(auth.PermSkillsWrite), skillHandler.Delete)
 
 			// Images
-			protected.POST("/images/upload", imageHandler.Upload)
-			protected.DELETE("/images/:id", imageHandler.Delete)
-			
+			protected.POST("/images/upload", can(auth.PermImagesUpload), imageHandler.Upload)
+			protected.DELETE("/images/:id", can(auth.PermImagesDelete), imageHandler.Delete)
+
 			// Contact Messages (Read)
-			protected.GET("/messages", contactHandler.GetAllMessages)
+			protected.GET("/messages", can(auth.PermMessagesRead), contactHandler.GetAllMessages)
 
 			// Experiences (Admin)
-			protected.GET("/experiences", experienceHandler.GetAdminExperiences)
-			protected.POST("/experiences", experienceHandler.CreateExperience)
-			protected.PUT("/experiences/:id", experienceHandler.UpdateExperience)
-			protected.DELETE("/experiences/:id", experienceHandler.DeleteExperience)
+			protected.GET("/experiences", can(auth.PermExperiencesRead), experienceHandler.GetAdminExperiences)
+			protected.POST("/experiences", can(auth.PermExperiencesWrite), experienceHandler.CreateExperience)
+			protected.PUT("/experiences/:id", can(auth.PermExperiencesWrite), experienceHandler.UpdateExperience)
+			protected.DELETE("/experiences/:id", can(auth.PermExperiencesWrite), experienceHandler.DeleteExperience)
+			protected.GET("/experiences/:id/revisions", can(auth.PermExperiencesRead), revisionHandler.List(revisions.EntityExperience))
+			protected.GET("/experiences/:id/revisions/diff", can(auth.PermExperiencesRead), revisionHandler.Diff(revisions.EntityExperience))
+			protected.GET("/experiences/:id/revisions/:number", can(auth.PermExperiencesRead), revisionHandler.Get(revisions.EntityExperience))
+			protected.POST("/experiences/:id/revisions/:number/restore", can(auth.PermExperiencesWrite), experienceHandler.RestoreExperienceRevision)
+			protected.GET("/experiences/:id/autosave", can(auth.PermExperiencesWrite), revisionHandler.GetDraft(revisions.EntityExperience))
+			protected.PUT("/experiences/:id/autosave", can(auth.PermExperiencesWrite), revisionHandler.SaveDraft(revisions.EntityExperience, experiences.UpdateExperienceRequest{}))
+			protected.DELETE("/experiences/:id/autosave", can(auth.PermExperiencesWrite), revisionHandler.DeleteDraft(revisions.EntityExperience))
 		}
 	}
 	
+	// Public keys for verifying admin access tokens
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
+
+	// Blog feeds, of all published posts or of one tag
+	r.GET("/feed.xml", feedHandler.RSS)
+	r.GET("/atom.xml", feedHandler.Atom)
+	r.GET("/feed.json", feedHandler.JSON)
+	r.GET("/tags/:slug/feed.xml", feedHandler.RSS)
+	r.GET("/tags/:slug/atom.xml", feedHandler.Atom)
+	r.GET("/tags/:slug/feed.json", feedHandler.JSON)
+
+	// Crawlers: sitemap, robots.txt and the IndexNow key file
+	r.GET("/sitemap.xml", seoHandler.Sitemap)
+	r.GET("/sitemaps/:page", seoHandler.SitemapPage)
+	r.GET("/robots.txt", seoHandler.Robots)
+	r.GET("/indexnow.txt", seoHandler.IndexNowKey)
+
 	// Static file serving for images
 	// Map /media to storage folder
 	// In production this might be handled by Nginx
 	r.Static("/media", "./storage")
+
+	// Background jobs
+	return []scheduler.Job{
+		{Name: "publish scheduled posts", Run: postService.ApplySchedule},
+	}
 }
@@ -0,0 +1,54 @@
+// Package scheduler runs periodic background jobs inside the server, such
+// as publishing posts whose scheduled time has come.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is run on every tick with the current time. Jobs must be idempotent:
+// every server instance runs them.
+type Job struct {
+	Name string
+	Run  func(now time.Time) error
+}
+
+// Start runs the jobs once and then every interval in a background
+// goroutine until ctx is done. The returned channel is closed once the
+// goroutine has stopped, after any running job has finished. A non-positive
+// interval disables the scheduler.
+func Start(ctx context.Context, interval time.Duration, jobs ...Job) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		log.Println("scheduler disabled")
+		close(done)
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runJobs(jobs)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
+
+func runJobs(jobs []Job) {
+	now := time.Now()
+	for _, job := range jobs {
+		if err := job.Run(now); err != nil {
+			log.Printf("scheduler: %s failed: %v", job.Name, err)
+		}
+	}
+}
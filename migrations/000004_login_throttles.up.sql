@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS login_throttles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR(320) NOT NULL UNIQUE,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    blocked_until TIMESTAMP WITH TIME ZONE,
+    locked BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(512),
+    ip_address VARCHAR(64),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- Keep logins that still hold a usable refresh token
+INSERT INTO sessions (id, user_id, last_seen_at, expires_at, created_at)
+SELECT family_id, user_id, MAX(created_at), MAX(expires_at), MIN(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
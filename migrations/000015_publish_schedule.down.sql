@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_projects_visible_until;
+DROP INDEX IF EXISTS idx_projects_visible_from;
+ALTER TABLE projects DROP COLUMN IF EXISTS visible_until;
+ALTER TABLE projects DROP COLUMN IF EXISTS visible_from;
+
+DROP INDEX IF EXISTS idx_posts_unpublish_at;
+DROP INDEX IF EXISTS idx_posts_publish_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
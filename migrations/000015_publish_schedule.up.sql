@@ -0,0 +1,9 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_posts_publish_at ON posts(publish_at);
+CREATE INDEX IF NOT EXISTS idx_posts_unpublish_at ON posts(unpublish_at);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS visible_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS visible_until TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_projects_visible_from ON projects(visible_from);
+CREATE INDEX IF NOT EXISTS idx_projects_visible_until ON projects(visible_until);
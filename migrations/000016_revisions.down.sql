@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS revision_drafts;
+DROP TABLE IF EXISTS revisions;
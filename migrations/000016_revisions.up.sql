@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    number INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    author_id UUID,
+    api_key_id UUID,
+    restored_from INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_revisions_entity_number ON revisions(entity_type, entity_id, number);
+
+CREATE TABLE IF NOT EXISTS revision_drafts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    base_revision INTEGER NOT NULL DEFAULT 0,
+    content JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_revision_drafts_entity_author ON revision_drafts(entity_type, entity_id, author_id);
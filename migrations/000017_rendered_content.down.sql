@@ -0,0 +1,9 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS reading_time;
+ALTER TABLE projects DROP COLUMN IF EXISTS word_count;
+ALTER TABLE projects DROP COLUMN IF EXISTS table_of_contents;
+ALTER TABLE projects DROP COLUMN IF EXISTS content_html;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS reading_time;
+ALTER TABLE posts DROP COLUMN IF EXISTS word_count;
+ALTER TABLE posts DROP COLUMN IF EXISTS table_of_contents;
+ALTER TABLE posts DROP COLUMN IF EXISTS content_html;
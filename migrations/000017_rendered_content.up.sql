@@ -0,0 +1,9 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_html TEXT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS table_of_contents JSONB;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS word_count INTEGER DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reading_time INTEGER DEFAULT 0;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS content_html TEXT;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS table_of_contents JSONB;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS word_count INTEGER DEFAULT 0;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS reading_time INTEGER DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_experiences_search_vector;
+DROP INDEX IF EXISTS idx_projects_search_vector;
+DROP INDEX IF EXISTS idx_posts_search_vector;
+
+ALTER TABLE experiences DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE experiences ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_experiences_search_vector ON experiences USING GIN (search_vector);
+
+UPDATE posts SET search_vector =
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(summary, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(content_markdown, '')), 'C');
+
+UPDATE projects SET search_vector =
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(content_markdown, '')), 'C');
+
+UPDATE experiences SET search_vector =
+    setweight(to_tsvector('simple', coalesce(position, '') || ' ' || coalesce(company, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B');
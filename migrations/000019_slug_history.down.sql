@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS slug_history;
+
+ALTER TABLE projects DROP COLUMN IF EXISTS slug_pinned;
+ALTER TABLE posts DROP COLUMN IF EXISTS slug_pinned;
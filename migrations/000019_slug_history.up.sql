@@ -0,0 +1,13 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slug_pinned BOOLEAN DEFAULT false;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS slug_pinned BOOLEAN DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS slug_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(50) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    entity_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_slug_history_slug ON slug_history(entity_type, slug);
+CREATE INDEX idx_slug_history_entity_id ON slug_history(entity_id);
@@ -0,0 +1 @@
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS description TEXT;
@@ -0,0 +1,3 @@
+-- Databases that ran 000005 before its default was corrected still default
+-- new users to owner.
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS search_lexicon (
+    table_name VARCHAR(20) NOT NULL,
+    row_id UUID NOT NULL,
+    word TEXT NOT NULL,
+    PRIMARY KEY (table_name, row_id, word)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_lexicon_word ON search_lexicon USING GIN (word gin_trgm_ops);
+
+INSERT INTO search_lexicon (table_name, row_id, word)
+SELECT DISTINCT 'posts', t.id, v.lexeme FROM posts t, unnest(t.search_vector) AS v
+ON CONFLICT DO NOTHING;
+
+INSERT INTO search_lexicon (table_name, row_id, word)
+SELECT DISTINCT 'projects', t.id, v.lexeme FROM projects t, unnest(t.search_vector) AS v
+ON CONFLICT DO NOTHING;
+
+INSERT INTO search_lexicon (table_name, row_id, word)
+SELECT DISTINCT 'experiences', t.id, v.lexeme FROM experiences t, unnest(t.search_vector) AS v
+ON CONFLICT DO NOTHING;
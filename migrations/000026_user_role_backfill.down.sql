@@ -0,0 +1,2 @@
+-- Roles restored by the up migration cannot be told apart from roles set
+-- since, so they are kept.
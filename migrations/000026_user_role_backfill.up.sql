@@ -0,0 +1,5 @@
+-- A role column added by the server's AutoMigrate defaults every existing
+-- account to viewer, leaving nobody able to manage users. Only then, when no
+-- account has any other role, are the accounts made owners again.
+UPDATE users SET role = 'owner'
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE role <> 'viewer');